@@ -1,73 +1,74 @@
-package main
+package day01
 
 import (
 	"advent_2021/extra"
-	"bufio"
-	"fmt"
-	"log"
-	"os"
+	"advent_2021/solver"
+	"errors"
+	"io"
 )
 
-func firstExercise() int {
-	file, err := os.Open("inputs/day01_exercise01.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func(file *os.File) {
-		err := file.Close()
+func init() {
+	solver.Register(1, func() solver.Solver { return &Puzzle{} })
+}
+
+// Puzzle holds the depth measurements of the sonar sweep.
+type Puzzle struct {
+	depths []int
+}
+
+func (p *Puzzle) Parse(input io.Reader) error {
+	scanner := extra.NewScanner(input)
+
+	for scanner.Scan() {
+		depth, err := scanner.Line().Int()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-	}(file)
+		p.depths = append(p.depths, depth)
+	}
+
+	return scanner.Err()
+}
 
-	scanner := bufio.NewScanner(file)
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	if len(p.depths) < 1 {
+		return solver.Answer{}, errors.New("there are no measurements")
+	}
 
-	scanner.Scan()
 	var result int
 
 	// We bootstrap the variables before looping through the input.
 	var (
 		previous int
-		current  = extra.ConvertToInt(scanner.Text())
+		current  = p.depths[0]
 	)
 
-	for scanner.Scan() {
-		previous, current = current, extra.ConvertToInt(scanner.Text())
+	for _, depth := range p.depths[1:] {
+		previous, current = current, depth
 		if previous < current {
 			result++
 		}
 	}
 
-	return result
+	return solver.Number(result), nil
 }
 
-func secondExercise() int {
-	file, err := os.Open("inputs/day01_exercise02.txt")
-	if err != nil {
-		log.Fatal(err)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	if len(p.depths) < 3 {
+		return solver.Answer{}, errors.New("there are not enough measurements for a window")
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}(file)
 
-	scanner := bufio.NewScanner(file)
 	var result int
 
 	// We populate the first three values to bootstrap the loop
-	var first int
-	scanner.Scan()
-	var second = extra.ConvertToInt(scanner.Text())
-	scanner.Scan()
-	var third = extra.ConvertToInt(scanner.Text())
-	scanner.Scan()
-	var fourth = extra.ConvertToInt(scanner.Text())
-
-	// Now we can easily traverse the file and generate all the subsegments.
-	for scanner.Scan() {
-		first, second, third, fourth = second, third, fourth, extra.ConvertToInt(scanner.Text())
+	var (
+		first                 int
+		second, third, fourth = p.depths[0], p.depths[1], p.depths[2]
+	)
+
+	// Now we can easily traverse the input and generate all the subsegments.
+	for _, depth := range p.depths[3:] {
+		first, second, third, fourth = second, third, fourth, depth
 		firstSegment := first + second + third
 		secondSegment := second + third + fourth
 		if firstSegment < secondSegment {
@@ -75,10 +76,5 @@ func secondExercise() int {
 		}
 	}
 
-	return result
-}
-
-func main() {
-	fmt.Printf("First exercise answer: %d.\n", firstExercise())
-	fmt.Printf("Second exercise answer: %d.\n", secondExercise())
+	return solver.Number(result), nil
 }
@@ -1,96 +1,80 @@
-package main
+package day02
 
 import (
 	"advent_2021/extra"
-	"bufio"
-	"fmt"
-	"log"
-	"os"
-	"regexp"
+	"advent_2021/solver"
+	"io"
 )
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day02_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
+func init() {
+	solver.Register(2, func() solver.Solver { return &Puzzle{} })
+}
 
-	reg, err := regexp.Compile("^(?P<command>[a-z]+) (?P<distance>[0-9]+)$")
-	if err != nil {
-		return -1, err
-	}
+// Command is a single instruction for the submarine, like `forward 5`.
+type Command struct {
+	name  string
+	units int
+}
+
+// Puzzle holds the planned course of the submarine.
+type Puzzle struct {
+	commands []Command
+}
 
-	var (
-		scanner                   = bufio.NewScanner(file)
-		horizontalDistance, depth int
-	)
+func (p *Puzzle) Parse(input io.Reader) error {
+	scanner := extra.NewScanner(input)
 
 	for scanner.Scan() {
-		match := reg.FindStringSubmatch(scanner.Text())
-		var command, distance = match[1], extra.ConvertToInt(match[2])
+		name, rawUnits, err := scanner.Line().Cut(" ")
+		if err != nil {
+			return err
+		}
+		if name.Text != "forward" && name.Text != "up" && name.Text != "down" {
+			return name.Errorf(0, "unknown command %q", name.Text)
+		}
 
-		switch command {
-		case "forward":
-			horizontalDistance += distance
-		case "up":
-			depth -= distance
-		case "down":
-			depth += distance
+		units, err := rawUnits.Int()
+		if err != nil {
+			return err
 		}
+		p.commands = append(p.commands, Command{name: name.Text, units: units})
 	}
 
-	return horizontalDistance * depth, nil
+	return scanner.Err()
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day02_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	var horizontalDistance, depth int
 
-	reg, err := regexp.Compile("^(?P<command>[a-z]+) (?P<units>[0-9]+)$")
-	if err != nil {
-		return -1, err
+	for _, command := range p.commands {
+		switch command.name {
+		case "forward":
+			horizontalDistance += command.units
+		case "up":
+			depth -= command.units
+		case "down":
+			depth += command.units
+		}
 	}
 
-	var (
-		scanner                                = bufio.NewScanner(file)
-		horizontalDistance, inclination, depth int
-	)
+	return solver.Number(horizontalDistance * depth), nil
+}
 
-	for scanner.Scan() {
-		match := reg.FindStringSubmatch(scanner.Text())
-		var command, units = match[1], extra.ConvertToInt(match[2])
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	var horizontalDistance, inclination, depth int
 
+	for _, command := range p.commands {
 		// We choose the `switch` over an `if`-`else` as it is more performant in this case.
-		switch command {
+		switch command.name {
 		case "forward":
-			horizontalDistance += units
-			depth += units * inclination
+			horizontalDistance += command.units
+			depth += command.units * inclination
 		case "up":
-			inclination -= units
+			inclination -= command.units
 		case "down":
-			inclination += units
+			inclination += command.units
 		}
 	}
 
-	return horizontalDistance * depth, nil
-}
-
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The distance is: %d.\n", firstResult)
-	}
-
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The distance is: %d.\n", secondResult)
-	}
+	return solver.Number(horizontalDistance * depth), nil
 }
@@ -1,32 +1,56 @@
-package main
+package day03
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/solver"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io"
 	"strconv"
+	"strings"
 )
 
-func firstExercise() (int64, error) {
-	file, err := os.Open("inputs/day03_exercise01.txt")
-	if err != nil {
-		return -1, err
+func init() {
+	solver.Register(3, func() solver.Solver { return &Puzzle{} })
+}
+
+// Puzzle holds the binary numbers of the diagnostic report.
+type Puzzle struct {
+	archive []string
+}
+
+func (p *Puzzle) Parse(input io.Reader) error {
+	scanner := extra.NewScanner(input)
+
+	for scanner.Scan() {
+		line := scanner.Line()
+		if index := strings.IndexFunc(line.Text, func(r rune) bool { return r != '0' && r != '1' }); index >= 0 {
+			return line.Errorf(index, "invalid binary digit %q", line.Text[index])
+		}
+		if len(p.archive) > 0 && len(line.Text) != len(p.archive[0]) {
+			return line.Errorf(0, "number has %d bits, expected %d", len(line.Text), len(p.archive[0]))
+		}
+
+		p.archive = append(p.archive, line.Text)
+	}
+
+	return scanner.Err()
+}
+
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	if len(p.archive) == 0 {
+		return solver.Answer{}, errors.New("the report is empty")
 	}
-	defer extra.CloseFile(file)
 
 	var (
-		counter       [12]int
+		counter       = make([]int, len(p.archive[0]))
 		gammaString   string
 		epsilonString string
-		scanner       = bufio.NewScanner(file)
 	)
 
 	// We count the number or 1s and 0s per column
-	for scanner.Scan() {
-		for pos, char := range scanner.Text() {
+	for _, line := range p.archive {
+		for pos, char := range line {
 			if char == '1' {
 				counter[pos]++
 			} else {
@@ -45,61 +69,49 @@ func firstExercise() (int64, error) {
 			gammaString += "0"
 			epsilonString += "1"
 		} else {
-			return -1, errors.New("Same number of 1s and 0s.  Wrong input!")
+			return solver.Answer{}, errors.New("Same number of 1s and 0s.  Wrong input!")
 		}
 	}
 
 	gammaValue, err := strconv.ParseInt(gammaString, 2, 0)
 	if err != nil {
-		return -1, err
+		return solver.Answer{}, err
 	}
 
 	epsilonValue, err := strconv.ParseInt(epsilonString, 2, 0)
 	if err != nil {
-		return -1, err
+		return solver.Answer{}, err
 	}
 
-	return gammaValue * epsilonValue, nil
+	return solver.Number(int(gammaValue * epsilonValue)), nil
 }
 
-func secondExercise() (int64, error) {
-	file, err := os.Open("inputs/day03_exercise02.txt")
-	if err != nil {
-		return -1, err
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	if len(p.archive) == 0 {
+		return solver.Answer{}, errors.New("the report is empty")
 	}
-	defer extra.CloseFile(file)
 
-	var (
-		archive []string
-		scanner = bufio.NewScanner(file)
-	)
-
-	// We store the contents of the file in an array, we will as well already count the digits in the first position
-	for scanner.Scan() {
-		archive = append(archive, scanner.Text())
-	}
-
-	binaryOxygenRating, err := findOxygenRating(archive, 0)
+	binaryOxygenRating, err := findOxygenRating(p.archive, 0)
 	if err != nil {
-		return -1, err
+		return solver.Answer{}, err
 	}
 
-	binaryCO2Rating, err := findCO2Rating(archive, 0)
+	binaryCO2Rating, err := findCO2Rating(p.archive, 0)
 	if err != nil {
-		return -1, err
+		return solver.Answer{}, err
 	}
 
 	oxygenRating, err := strconv.ParseInt(binaryOxygenRating[0], 2, 0)
 	if err != nil {
-		return -1, err
+		return solver.Answer{}, err
 	}
 
 	CO2Rating, err := strconv.ParseInt(binaryCO2Rating[0], 2, 0)
 	if err != nil {
-		return -1, err
+		return solver.Answer{}, err
 	}
 
-	return oxygenRating * CO2Rating, nil
+	return solver.Number(int(oxygenRating * CO2Rating)), nil
 }
 
 // classify returns two arrays of strings, dividing elements depending on if they contain 0 or 1 in the given position.
@@ -119,11 +131,18 @@ func classify(list []string, position int) ([]string, []string) {
 	return beginWithZero, beginWithOne
 }
 
+// sameNumbers returns the error for a list left with copies of the same number once every position was checked.
+func sameNumbers(list []string) error {
+	return fmt.Errorf("the report has %d copies of %s, so there is no single rating", len(list), list[0])
+}
+
 // findOxygenRating recursively filters a given string array, checking a given position on each of its strings.
 // In this case, we select the most popular value in the given position.
 func findOxygenRating(list []string, position int) ([]string, error) {
 	if len(list) == 1 {
 		return list, nil
+	} else if position == len(list[0]) {
+		return nil, sameNumbers(list)
 	} else {
 		beginWithZero, beginWithOne := classify(list, position)
 		newPosition := position + 1
@@ -137,34 +156,22 @@ func findOxygenRating(list []string, position int) ([]string, error) {
 }
 
 // findCO2Rating recursively filters a given string array, checking a given position on each of its strings.
-// In this case, we select the least popular value in the given position.
+// In this case, we select the least popular value in the given position, unless no string has it.
 func findCO2Rating(list []string, position int) ([]string, error) {
 	if len(list) == 1 {
 		return list, nil
+	} else if position == len(list[0]) {
+		return nil, sameNumbers(list)
 	} else {
 		beginWithZero, beginWithOne := classify(list, position)
 		newPosition := position + 1
 
-		if len(beginWithOne) < len(beginWithZero) {
+		if len(beginWithOne) == 0 || len(beginWithZero) == 0 {
+			return findCO2Rating(list, newPosition)
+		} else if len(beginWithOne) < len(beginWithZero) {
 			return findCO2Rating(beginWithOne, newPosition)
 		} else {
 			return findCO2Rating(beginWithZero, newPosition)
 		}
 	}
 }
-
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
-
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
-	}
-}
@@ -0,0 +1,62 @@
+package day03
+
+import (
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "198", PartTwo: "230"},
+	})
+}
+
+func TestRatingsOfSharedBits(t *testing.T) {
+	// Both numbers start with 0, so the CO2 rating cannot keep those starting with 1.
+	p := &Puzzle{archive: []string{"0110", "0111"}}
+	if got, err := p.PartTwo(); err != nil || got != solver.Number(42) {
+		t.Errorf("PartTwo() = %v, %v, want 42", got, err)
+	}
+
+	// Copies of a number are never told apart.
+	p = &Puzzle{archive: []string{"101", "101", "010"}}
+	if got, err := p.PartTwo(); err == nil {
+		t.Errorf("PartTwo() = %v, want an error", got)
+	}
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 3, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+func TestRatings(t *testing.T) {
+	archive := strings.Fields(example)
+
+	tests := []struct {
+		name   string
+		rating func([]string, int) ([]string, error)
+		want   []string
+	}{
+		{"oxygen", findOxygenRating, []string{"10111"}},
+		{"CO2", findCO2Rating, []string{"01010"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rating(archive, 0)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,51 +1,68 @@
-package main
+package day04
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/solver"
 	"errors"
-	"fmt"
-	"log"
-	"os"
-	"strings"
+	"io"
 )
 
+func init() {
+	solver.Register(4, func() solver.Solver { return &Puzzle{} })
+}
+
+// marked is the value a number on a board takes once it has been drawn.  Bingo numbers are never negative.
+const marked = -1
+
 // Matrix is the type that defined a bingo board.  The board has as property a boolean that marks if it has already
 // won, so we can quickly dismiss it.
 type Matrix struct {
-	grid  [][]string
+	grid  [][]int
 	valid bool
 }
 
 // createMatrix will return a matrix expressed as a slice of slices when fed a pointer to a scanner.
-func createMatrix(scanner *bufio.Scanner) (matrix Matrix) {
-	temporalMatrix := make([][]string, 0)
+func createMatrix(scanner *extra.Scanner) (matrix Matrix, err error) {
+	temporalMatrix := make([][]int, 0)
 
 	for i := 0; i < 5; i++ {
-		row := strings.Fields(scanner.Text())
-		temporalMatrix = append(temporalMatrix, [][]string{row}...)
-		scanner.Scan()
+		line := scanner.Line()
+		row, err := line.IntList("")
+		if err != nil {
+			return matrix, err
+		}
+		if len(row) != 5 {
+			return matrix, line.Errorf(0, "board row has %d numbers, expected 5", len(row))
+		}
+
+		temporalMatrix = append(temporalMatrix, row)
+		if !scanner.Scan() && i < 4 {
+			return matrix, scanner.Errorf("board has %d rows, expected 5", i+1)
+		}
 	}
 
 	matrix.grid = temporalMatrix
 	matrix.valid = true
 
-	return matrix
+	return matrix, nil
 }
 
 // stitchMatrices will read the given file and generate a slice of matrices from it.
-func stitchMatrices(scanner *bufio.Scanner) (matrices []Matrix) {
+func stitchMatrices(scanner *extra.Scanner) (matrices []Matrix, err error) {
 
 	for scanner.Scan() {
 		if scanner.Text() == "" {
 			continue
 		} else {
-			newMatrix := createMatrix(scanner)
+			newMatrix, err := createMatrix(scanner)
+			if err != nil {
+				return nil, err
+			}
 			matrices = append(matrices, newMatrix)
 		}
 	}
 
-	return matrices
+	return matrices, nil
 }
 
 // evaluateWinCondition will check if the matrix is a winner one and return the result.  It will also invalidate this
@@ -54,22 +71,22 @@ func (matrix *Matrix) evaluateWinCondition() bool {
 
 	for i := 0; i < 5; i++ {
 		// Checking the rows
-		if matrix.grid[i][0] == "X" &&
-			matrix.grid[i][1] == "X" &&
-			matrix.grid[i][2] == "X" &&
-			matrix.grid[i][3] == "X" &&
-			matrix.grid[i][4] == "X" {
+		if matrix.grid[i][0] == marked &&
+			matrix.grid[i][1] == marked &&
+			matrix.grid[i][2] == marked &&
+			matrix.grid[i][3] == marked &&
+			matrix.grid[i][4] == marked {
 
 			matrix.valid = false
 			return true
 		}
 
 		// Checking the columns
-		if matrix.grid[0][i] == "X" &&
-			matrix.grid[1][i] == "X" &&
-			matrix.grid[2][i] == "X" &&
-			matrix.grid[3][i] == "X" &&
-			matrix.grid[4][i] == "X" {
+		if matrix.grid[0][i] == marked &&
+			matrix.grid[1][i] == marked &&
+			matrix.grid[2][i] == marked &&
+			matrix.grid[3][i] == marked &&
+			matrix.grid[4][i] == marked {
 
 			matrix.valid = false
 			return true
@@ -85,8 +102,8 @@ func (matrix *Matrix) calculatePoints(number int) int {
 
 	for _, row := range matrix.grid {
 		for _, value := range row {
-			if value != "X" {
-				points += extra.ConvertToInt(value)
+			if value != marked {
+				points += value
 			}
 		}
 	}
@@ -94,86 +111,92 @@ func (matrix *Matrix) calculatePoints(number int) int {
 	return points * number
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day04_exercise01.txt")
-	if err != nil {
-		return -1, err
+// copyMatrix returns a deep copy of the matrix, so it can be marked without altering the original board.
+func (matrix *Matrix) copyMatrix() Matrix {
+	grid := make([][]int, len(matrix.grid))
+	for i := range matrix.grid {
+		grid[i] = append([]int(nil), matrix.grid[i]...)
+	}
+
+	return Matrix{grid: grid, valid: matrix.valid}
+}
+
+// Puzzle holds the numbers drawn and the bingo boards of the giant squid game.
+type Puzzle struct {
+	numbers  []int
+	matrices []Matrix
+}
+
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+
+	if !scanner.Scan() {
+		return scanner.Errorf("missing the numbers to draw")
+	}
+	if p.numbers, err = scanner.Line().IntList(","); err != nil {
+		return err
+	}
+	if p.matrices, err = stitchMatrices(scanner); err != nil {
+		return err
+	}
+
+	return scanner.Err()
+}
+
+// freshMatrices returns unmarked copies of the boards of the puzzle.
+func (p *Puzzle) freshMatrices() []Matrix {
+	matrices := make([]Matrix, len(p.matrices))
+	for i := range p.matrices {
+		matrices[i] = p.matrices[i].copyMatrix()
 	}
-	defer extra.CloseFile(file)
 
-	scanner := bufio.NewScanner(file)
+	return matrices
+}
 
-	scanner.Scan()
-	numbers := strings.Split(scanner.Text(), ",")
-	matrices := stitchMatrices(scanner)
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	matrices := p.freshMatrices()
 
-	for i, number := range numbers {
+	for i, number := range p.numbers {
 		for x, matrix := range matrices {
 			for y, row := range matrix.grid {
 				for z, value := range row {
 					if value == number {
-						matrices[x].grid[y][z] = "X"
+						matrices[x].grid[y][z] = marked
 					}
 				}
 			}
 
 			if matrices[x].evaluateWinCondition() {
-				fmt.Printf("BINGO!\nFound after %d numbers (%s) in matrix %d:\n%s\n", i, number, x, matrices[x])
-				return matrices[x].calculatePoints(extra.ConvertToInt(number)), nil
+				solver.Debugf("BINGO!\nFound after %d numbers (%d) in matrix %d:\n%v\n", i, number, x, matrices[x])
+				return solver.Number(matrices[x].calculatePoints(number)), nil
 			}
 		}
 	}
 
-	return -1, errors.New("there is no good board")
+	return solver.Answer{}, errors.New("there is no good board")
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day04_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	scanner := bufio.NewScanner(file)
-
-	scanner.Scan()
-	numbers := strings.Split(scanner.Text(), ",")
-	matrices := stitchMatrices(scanner)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	matrices := p.freshMatrices()
 	solution, lastNumber := Matrix{valid: true}, 0
 
-	for _, number := range numbers {
+	for _, number := range p.numbers {
 		for x, matrix := range matrices {
 			if matrix.valid {
 				for y, row := range matrix.grid {
 					for z, value := range row {
 						if value == number {
-							matrices[x].grid[y][z] = "X"
+							matrices[x].grid[y][z] = marked
 						}
 					}
 				}
 				if matrices[x].evaluateWinCondition() {
-					solution, lastNumber = matrices[x], extra.ConvertToInt(number)
+					solution, lastNumber = matrices[x], number
 				}
 			}
 		}
 	}
 
-	fmt.Printf("BINGO!\nFound after number %d in matrix :\n%s\n", lastNumber, solution)
-	return solution.calculatePoints(lastNumber), nil
-}
-
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
-
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
-	}
+	solver.Debugf("BINGO!\nFound after number %d in matrix :\n%v\n", lastNumber, solution)
+	return solver.Number(solution.calculatePoints(lastNumber)), nil
 }
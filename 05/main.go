@@ -1,75 +1,89 @@
-package main
+package day05
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
 	"fmt"
-	"log"
-	"os"
-	"strings"
+	"io"
 )
 
+func init() {
+	solver.Register(5, func() solver.Solver { return &Puzzle{} })
+}
+
 // parsePoints will extract two Point from a line of text from our input.
-func parsePoints(scanner *bufio.Scanner) (origin Point, destination Point) {
-	rawInput := strings.Split(scanner.Text(), " -> ")
-	rawOrigin := strings.Split(rawInput[0], ",")
-	rawDestination := strings.Split(rawInput[1], ",")
+func parsePoints(line extra.Line) (origin grid.Point, destination grid.Point, err error) {
+	rawOrigin, rawDestination, err := line.Rule()
+	if err != nil {
+		return origin, destination, err
+	}
 
-	origin.x, origin.y = extra.ConvertToInt(rawOrigin[0]), extra.ConvertToInt(rawOrigin[1])
-	destination.x, destination.y = extra.ConvertToInt(rawDestination[0]), extra.ConvertToInt(rawDestination[1])
+	if origin.X, origin.Y, err = rawOrigin.Pair(); err != nil {
+		return origin, destination, err
+	}
+	if destination.X, destination.Y, err = rawDestination.Pair(); err != nil {
+		return origin, destination, err
+	}
+	if origin.X < 0 || origin.Y < 0 || destination.X < 0 || destination.Y < 0 {
+		return origin, destination, line.Errorf(0, "coordinates cannot be negative")
+	}
 
-	return origin, destination
+	return origin, destination, nil
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day05_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
+// Line is a line of hydrothermal vents, as described by one row of the input.
+type Line struct {
+	origin, destination grid.Point
+}
+
+// Puzzle holds the lines of vents found in the ocean floor.
+type Puzzle struct {
+	lines []Line
+}
 
-	scanner := bufio.NewScanner(file)
-	diagram := Diagram{height: 0, width: 0, grid: make([][]int, 0)}
+func (p *Puzzle) Parse(input io.Reader) error {
+	scanner := extra.NewScanner(input)
 
 	for scanner.Scan() {
-		origin, destination := parsePoints(scanner)
-		diagram.drawLine(origin, destination, false)
+		origin, destination, err := parsePoints(scanner.Line())
+		if err != nil {
+			return err
+		}
+		p.lines = append(p.lines, Line{origin: origin, destination: destination})
 	}
 
-	//diagram.drawDiagram()
-	return diagram.calculateDangerousPoints(), nil
+	return scanner.Err()
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day05_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-	scanner := bufio.NewScanner(file)
-	diagram := Diagram{height: 0, width: 0, grid: make([][]int, 0)}
+// drawDiagram draws all the lines of the puzzle in a new Diagram, including the diagonal ones if requested.
+func (p *Puzzle) drawDiagram(drawDiagonals bool) *Diagram {
+	diagram := newDiagram()
 
-	for scanner.Scan() {
-		origin, destination := parsePoints(scanner)
-		diagram.drawLine(origin, destination, true)
+	for _, line := range p.lines {
+		diagram.drawLine(line.origin, line.destination, drawDiagonals)
 	}
 
-	//diagram.drawDiagram()
-	return diagram.calculateDangerousPoints(), nil
+	return diagram
 }
 
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	diagram := p.drawDiagram(false)
+	return solver.Number(diagram.calculateDangerousPoints()), nil
+}
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	diagram := p.drawDiagram(true)
+	return solver.Number(diagram.calculateDangerousPoints()), nil
+}
+
+// Draw returns the number of vents on each point of the diagram of a part.
+func (p *Puzzle) Draw(part int) (*grid.Grid[int], picture.Palette, error) {
+	if part != 1 && part != 2 {
+		return nil, nil, fmt.Errorf("invalid part %d, it must be 1 or 2", part)
 	}
+
+	vents := p.drawDiagram(part == 2).vents
+	return vents, picture.Heat(picture.Range(vents)), nil
 }
@@ -0,0 +1,113 @@
+package day05
+
+import (
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "5", PartTwo: "12"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 5, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+// points returns the points given as a list of x,y coordinates.
+func points(coordinates ...int) (result []grid.Point) {
+	for i := 0; i+1 < len(coordinates); i += 2 {
+		result = append(result, grid.Point{X: coordinates[i], Y: coordinates[i+1]})
+	}
+
+	return result
+}
+
+func TestDrawLine(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          []grid.Point
+		drawDiagonals bool
+		want          []grid.Point
+	}{
+		{"horizontal", points(1, 2, 3, 2), false, points(1, 2, 2, 2, 3, 2)},
+		{"backwards vertical", points(2, 3, 2, 1), false, points(2, 1, 2, 2, 2, 3)},
+		{"ignored diagonal", points(0, 0, 2, 2), false, nil},
+		{"diagonal", points(0, 0, 2, 2), true, points(0, 0, 1, 1, 2, 2)},
+		{"backwards diagonal", points(3, 1, 1, 3), true, points(3, 1, 2, 2, 1, 3)},
+		{"other angle", points(0, 0, 1, 3), true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := newDiagram()
+			diagram.drawLine(tt.line[0], tt.line[1], tt.drawDiagonals)
+
+			want := make(map[grid.Point]bool)
+			for _, point := range tt.want {
+				want[point] = true
+			}
+
+			diagram.vents.Each(func(point grid.Point, vents int) {
+				expected := 0
+				if want[point] {
+					expected = 1
+				}
+				if vents != expected {
+					t.Errorf("vents at %d,%d = %d, want %d", point.X, point.Y, vents, expected)
+				}
+			})
+		})
+	}
+}
+
+func TestDraw(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+
+	vents, palette, err := p.Draw(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	vents.Print(&output, func(count int) string {
+		if count == 0 {
+			return "."
+		}
+		return strconv.Itoa(count)
+	})
+	want := `.......1..
+..1....1..
+..1....1..
+.......1..
+.112111211
+..........
+..........
+..........
+..........
+222111....
+`
+	if output.String() != want {
+		t.Errorf("Draw(1) =\n%swant\n%s", output.String(), want)
+	}
+	if palette(0) != picture.Black || palette(2) != picture.White {
+		t.Error("Draw(1) palette does not go from black to white")
+	}
+
+	if _, _, err := p.Draw(3); err == nil {
+		t.Error("Draw() accepted part 3")
+	}
+}
@@ -1,23 +1,32 @@
-package main
+package day06
 
 import (
 	"advent_2021/extra"
-	"bufio"
-	"fmt"
-	"log"
-	"os"
-	"strings"
+	"advent_2021/solver"
+	"io"
 )
 
-// sortIntoFishPool Given an array with the program's input, an array of strings.  It will produce another integer
-// array where each position represents the number of fish in that state of their reproductive cycle.  For example when
-// a fish is in the position 0, it means it will reproduce in the next cycle.
-func sortIntoFishPool(input []string) (result [9]int) {
-	for i := range input {
-		result[extra.ConvertToInt(input[i])]++
+func init() {
+	solver.Register(6, func() solver.Solver { return &Puzzle{} })
+}
+
+// sortIntoFishPool Given the program's input, a line of comma separated timers.  It will produce an integer array
+// where each position represents the number of fish in that state of their reproductive cycle.  For example when a
+// fish is in the position 0, it means it will reproduce in the next cycle.
+func sortIntoFishPool(line extra.Line) (result [9]int, err error) {
+	for _, field := range line.Split(",") {
+		timer, err := field.Int()
+		if err != nil {
+			return result, err
+		}
+		if timer < 0 || timer >= len(result) {
+			return result, field.Errorf(0, "timer %d out of range 0-%d", timer, len(result)-1)
+		}
+
+		result[timer]++
 	}
 
-	return result
+	return result, nil
 }
 
 // countFish will just iterate over the array of fish and aggregate how many are on each life cycle.
@@ -29,11 +38,8 @@ func countFish(input [9]int) (result int) {
 	return result
 }
 
-// fishLife will simulate any number of days in their life, given a buffer from where to read the initial population.
-func fishLife(scanner *bufio.Scanner, days int) [9]int {
-	input := strings.Split(scanner.Text(), ",")
-	fishPool := sortIntoFishPool(input)
-
+// fishLife will simulate any number of days in their life, given their initial population.
+func fishLife(fishPool [9]int, days int) [9]int {
 	for i := 0; i < days; i++ {
 		//fmt.Printf("Day %d:\t%+v\n", i, fishPool)
 		var tmpPool [9]int
@@ -51,48 +57,32 @@ func fishLife(scanner *bufio.Scanner, days int) [9]int {
 	return fishPool
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day06_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	scanner := bufio.NewScanner(file)
-	scanner.Scan()
-
-	fishPool := fishLife(scanner, 80)
-
-	return countFish(fishPool), nil
+// Puzzle holds the initial population of lanternfish, sorted by their reproductive state.
+type Puzzle struct {
+	fishPool [9]int
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day06_exercise01.txt")
-	if err != nil {
-		return -1, err
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+	if !scanner.Scan() {
+		return scanner.Errorf("missing the initial population")
+	}
+
+	if p.fishPool, err = sortIntoFishPool(scanner.Line()); err != nil {
+		return err
 	}
-	defer extra.CloseFile(file)
 
-	scanner := bufio.NewScanner(file)
-	scanner.Scan()
+	return scanner.Err()
+}
 
-	fishPool := fishLife(scanner, 256)
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	fishPool := fishLife(p.fishPool, 80)
 
-	return countFish(fishPool), nil
+	return solver.Number(countFish(fishPool)), nil
 }
 
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	fishPool := fishLife(p.fishPool, 256)
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
-	}
+	return solver.Number(countFish(fishPool)), nil
 }
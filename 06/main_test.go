@@ -0,0 +1,21 @@
+package day06
+
+import (
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "5934", PartTwo: "26984457539"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 6, func() solver.Solver { return &Puzzle{} }, example)
+}
@@ -1,26 +1,24 @@
-package main
+package day07
 
 import (
 	"advent_2021/extra"
-	"bufio"
-	"fmt"
-	"log"
+	"advent_2021/solver"
+	"io"
 	"math"
-	"os"
 	"sort"
-	"strings"
 )
 
-func loadValues(scanner *bufio.Scanner) []int {
-	scanner.Scan()
-	stringInput := strings.Split(scanner.Text(), ",")
-	result := make([]int, len(stringInput))
+func init() {
+	solver.Register(7, func() solver.Solver { return &Puzzle{} })
+}
 
-	for i, value := range stringInput {
-		result[i] = extra.ConvertToInt(value)
+// loadValues reads the comma separated positions of the crabs from the first line of the input.
+func loadValues(scanner *extra.Scanner) ([]int, error) {
+	if !scanner.Scan() {
+		return nil, scanner.Errorf("missing the crab positions")
 	}
 
-	return result
+	return scanner.Line().IntList(",")
 }
 
 // calculateMedian returns a rounded value for the median of the elements in an integer slice.
@@ -31,7 +29,7 @@ func calculateMedian(positions []int) (result int) {
 
 	mid := len(tmp) / 2
 
-	if mid%2 == 0 {
+	if len(tmp)%2 == 0 {
 		return (tmp[mid-1] + tmp[mid]) / 2
 	} else {
 		return tmp[mid]
@@ -68,47 +66,36 @@ func calculateFuelIncrementally(positions []int, target int) (result int) {
 	return result
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day07_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	// We use the median as its value separates in two equal half the values in the provided array.
-	input := loadValues(bufio.NewScanner(file))
-	median := calculateMedian(input)
-	return calculateFuel(input, median), nil
+// Puzzle holds the horizontal positions of the crabs.
+type Puzzle struct {
+	positions []int
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day07_exercise01.txt")
-	if err != nil {
-		return -1, err
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+	if p.positions, err = loadValues(scanner); err != nil {
+		return err
 	}
-	defer extra.CloseFile(file)
 
-	// Since we are vulnerable to the extremes (They cost a lot of fuel) we can use the mean here, which is the average
-	// position for the crabs.  There is some margin of error here because we are rounding it, on a bright note we were
-	// lucky enough that the rounded value was good to obtain the solution of the exercise, otherwise we should have
-	// tried with the floor/ceiling of the float value of the mean.
-	input := loadValues(bufio.NewScanner(file))
-	mean := calculateMean(input)
-	return calculateFuelIncrementally(input, mean), nil
+	return scanner.Err()
 }
 
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	// We use the median as its value separates in two equal half the values in the provided array.
+	median := calculateMedian(p.positions)
+	return solver.Number(calculateFuel(p.positions, median)), nil
+}
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	// Since we are vulnerable to the extremes (They cost a lot of fuel) we can use the mean here, which is the average
+	// position for the crabs.  There is some margin of error here because we are rounding it, we got lucky with our
+	// input, but the published example needs the ceiling instead, so we try both the floor and the ceiling of the mean.
+	mean := calculateMean(p.positions)
+	floor := calculateFuelIncrementally(p.positions, mean)
+	ceiling := calculateFuelIncrementally(p.positions, mean+1)
+
+	if ceiling < floor {
+		return solver.Number(ceiling), nil
 	}
+	return solver.Number(floor), nil
 }
@@ -0,0 +1,23 @@
+package day07
+
+import (
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "37", PartTwo: "168"},
+		{Name: "single crab", Input: "5\n", PartOne: "0", PartTwo: "0"},
+		{Name: "odd number of crabs", Input: "1,2,3,4,100\n", PartOne: "101", PartTwo: "3883"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 7, func() solver.Solver { return &Puzzle{} }, example)
+}
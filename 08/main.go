@@ -1,43 +1,82 @@
-package main
+package day08
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/solver"
 	"errors"
-	"fmt"
-	"log"
-	"os"
-	"strconv"
+	"io"
 	"strings"
 )
 
+func init() {
+	solver.Register(8, func() solver.Solver { return &Puzzle{} })
+}
+
 // loadInput simply read the input text file and forges two slices of strings.  One containing the unique digits and the
 // other their correspondent four secret output digits.
 // In case of any error parsing the file, we ship an error to the caller.
-func loadInput(scanner *bufio.Scanner) (uniqueDigits [][]string, outputDigits [][]string, err error) {
+func loadInput(scanner *extra.Scanner) (uniqueDigits [][]string, outputDigits [][]string, err error) {
 	for scanner.Scan() {
-		splitByType := strings.Split(scanner.Text(), " | ")
-		if len(splitByType) != 2 {
-			return uniqueDigits, outputDigits, errors.New("something failed when reading the input")
+		rawUnique, rawOutput, err := scanner.Line().Cut(" | ")
+		if err != nil {
+			return uniqueDigits, outputDigits, err
+		}
+
+		unique, err := loadDigits(rawUnique, 10)
+		if err != nil {
+			return uniqueDigits, outputDigits, err
+		}
+		output, err := loadDigits(rawOutput, 4)
+		if err != nil {
+			return uniqueDigits, outputDigits, err
 		}
 
-		uniqueDigits = append(uniqueDigits, strings.Split(splitByType[0], " "))
-		outputDigits = append(outputDigits, strings.Split(splitByType[1], " "))
+		uniqueDigits = append(uniqueDigits, unique)
+		outputDigits = append(outputDigits, output)
 	}
 
 	return uniqueDigits, outputDigits, nil
 }
 
-// firstExercise just iterates the array of output digits and looks for those which length is known to be one of the
-// unique ones for 1, 4, 7 and 8.
-func firstExercise() (result int, err error) {
-	file, err := os.Open("inputs/day08_exercise01.txt")
+// loadDigits reads the expected amount of space separated digits from a section of a line, checking they are only
+// made of the segments a to g.
+func loadDigits(line extra.Line, expected int) ([]string, error) {
+	fields := line.Fields()
+	if len(fields) != expected {
+		return nil, line.Errorf(0, "found %d digits, expected %d", len(fields), expected)
+	}
+
+	result := make([]string, len(fields))
+	for i, field := range fields {
+		if index := strings.IndexFunc(field.Text, func(r rune) bool { return r < 'a' || r > 'g' }); index >= 0 {
+			return nil, field.Errorf(index, "invalid segment %q", field.Text[index])
+		}
+		result[i] = field.Text
+	}
+
+	return result, nil
+}
+
+// Puzzle holds the notes about the seven-segment displays, the ten unique digits and the four output digits of each.
+type Puzzle struct {
+	unique, output [][]string
+}
+
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+
+	p.unique, p.output, err = loadInput(scanner)
 	if err != nil {
-		return -1, err
+		return err
 	}
-	defer extra.CloseFile(file)
 
-	_, output, err := loadInput(bufio.NewScanner(file))
+	return scanner.Err()
+}
+
+// PartOne just iterates the array of output digits and looks for those which length is known to be one of the unique
+// ones for 1, 4, 7 and 8.
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	output, result := p.output, 0
 
 	for i := 0; i < len(output); i++ {
 		for q := 0; q < len(output[i]); q++ {
@@ -48,7 +87,7 @@ func firstExercise() (result int, err error) {
 		}
 	}
 
-	return result, nil
+	return solver.Number(result), nil
 }
 
 // decodeDigits will take an array with ten unique digits, break the code and return another slice of strings with ten
@@ -130,15 +169,15 @@ func decodeDigits(uniqueDigits []string) []string {
 }
 
 // translateDigit takes the word defining the segments of a digit and with the help of the dictionary providing the
-// translation, returns another string with the number it represents.
-func translateDigit(digit string, dictionary []string) (string, error) {
+// translation, returns the number it represents.
+func translateDigit(digit string, dictionary []string) (int, error) {
 	for i, number := range dictionary {
 		if len(digit) == len(number) && len(subtractSegments(digit, number)) == 0 {
-			return strconv.Itoa(i), nil
+			return i, nil
 		}
 	}
 
-	return "", errors.New("unable to translate")
+	return -1, errors.New("unable to translate")
 }
 
 // subtractSegments will find the letters in source that are not present in toCompare, then return them in a single
@@ -158,49 +197,24 @@ func subtractSegments(source string, toCompare string) (result string) {
 	return result
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day08_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	unique, output, err := loadInput(bufio.NewScanner(file))
-	dictionary := make([]string, 0)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	unique, output := p.unique, p.output
 	result := 0
 
 	for i := range unique {
-		for p := 0; p < 10; p++ {
-			dictionary = decodeDigits(unique[i])
-		}
+		dictionary := decodeDigits(unique[i])
 
-		secret := ""
+		secret := 0
 		for q := 0; q < 4; q++ {
 			secretDigit := output[i][q]
 			translatedDigit, err := translateDigit(secretDigit, dictionary)
 			if err != nil {
-				log.Fatal(err)
+				return solver.Answer{}, err
 			}
-			secret += translatedDigit
+			secret = secret*10 + translatedDigit
 		}
-		result += extra.ConvertToInt(secret)
+		result += secret
 	}
 
-	return result, nil
-}
-
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
-
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
-	}
+	return solver.Number(result), nil
 }
@@ -0,0 +1,49 @@
+package day08
+
+import (
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "26", PartTwo: "61229"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 8, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+func TestSubtractSegments(t *testing.T) {
+	tests := []struct {
+		source, toCompare, want string
+	}{
+		{"dab", "ab", "d"},
+		{"ab", "dab", ""},
+		{"acedgfb", "cdfbe", "ag"},
+		{"abc", "", "abc"},
+	}
+
+	for _, tt := range tests {
+		if got := subtractSegments(tt.source, tt.toCompare); got != tt.want {
+			t.Errorf("subtractSegments(%q, %q) = %q, want %q", tt.source, tt.toCompare, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeDigits(t *testing.T) {
+	unique := strings.Fields("acedgfb cdfbe gcdfa fbcad dab cefabd cdfgeb eafb cagedb ab")
+	want := []string{"cagedb", "ab", "gcdfa", "fbcad", "eafb", "cdfbe", "cdfgeb", "dab", "acedgfb", "cefabd"}
+
+	if got := decodeDigits(unique); !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeDigits() = %v, want %v", got, want)
+	}
+}
@@ -1,126 +1,64 @@
-package main
+package day09
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
 	"fmt"
-	"log"
-	"os"
+	"io"
 	"sort"
 )
 
-type Point struct {
-	x, y int
+func init() {
+	solver.Register(9, func() solver.Solver { return &Puzzle{} })
 }
 
-// loadMatrix will split the input into an array of integer arrays, so we can easily process the data.
-func loadMatrix(scanner *bufio.Scanner) [][]int {
-	result := make([][]int, 0)
-
-	for scanner.Scan() {
-		currentLine := scanner.Text()
-		temporalArray := make([]int, len(currentLine))
-
-		for i := range currentLine {
-			temporalArray[i] = extra.ConvertToInt(string(currentLine[i]))
-		}
-
-		result = append(result, temporalArray)
-	}
-
-	return result
+// Puzzle holds the heightmap of the cave floor.
+type Puzzle struct {
+	heightmap *grid.Grid[int]
 }
 
-// validatePosition returns true if the element chosen in a matrix is lesser than its adjacent ones.
-func validatePosition(matrix [][]int, x int, y int) (result bool) {
-	startX, endX, startY, endY := setLimits(matrix, x, y)
-	result = true
-
-	for i := startX; i <= endX; i++ {
-		for q := startY; q <= endY; q++ {
-			validation := !(x == i && y == q)
-			if validation {
-				source := matrix[x][y]
-				target := matrix[i][q]
-				result = result && (source < target)
-			}
-		}
-	}
-
-	return result
+// Parse reads the input as a grid of heights, so we can easily process the data.
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	p.heightmap, err = grid.ReadDigits(extra.NewScanner(input))
+	return err
 }
 
-// setLimits will return bounded values to traverse adjacent elements to a given position in the provided matrix, this
-// allows us to loop through these adjacent positions without the risk of going out of bounds.
-func setLimits(matrix [][]int, x int, y int) (int, int, int, int) {
-	startX, endX := x-1, x+1
-	if startX < 0 {
-		startX = x
-	}
-	if endX == len(matrix) {
-		endX = x
-	}
+// validatePosition returns true if the element chosen in a heightmap is lesser than its adjacent ones.
+func validatePosition(heightmap *grid.Grid[int], point grid.Point) bool {
+	source := heightmap.Get(point)
 
-	startY, endY := y-1, y+1
-	if startY < 0 {
-		startY = y
-	}
-	if endY == len(matrix[0]) {
-		endY = y
-	}
-	return startX, endX, startY, endY
-}
-
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day09_exercise01.txt")
-	if err != nil {
-		return -1, err
+	for _, neighbour := range heightmap.Neighbours8(point) {
+		if source >= heightmap.Get(neighbour) {
+			return false
+		}
 	}
-	defer extra.CloseFile(file)
 
-	matrix := loadMatrix(bufio.NewScanner(file))
-	riskLevel := 0
+	return true
+}
 
-	for i := range matrix {
-		for q := range matrix[i] {
-			if validatePosition(matrix, i, q) {
-				riskLevel += matrix[i][q] + 1
-			}
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	riskLevel := grid.Fold(p.heightmap, 0, func(riskLevel int, point grid.Point, height int) int {
+		if validatePosition(p.heightmap, point) {
+			riskLevel += height + 1
 		}
-	}
+		return riskLevel
+	})
 
-	return riskLevel, nil
+	return solver.Number(riskLevel), nil
 }
 
 // findNeighbours will check the top, left, right and bottom locations to a given point and evaluate if they belong to
 // the basin of the given point.
-func findNeighbours(matrix [][]int, point Point) (result []Point) {
-	result = []Point{point}
-	startX, endX, startY, endY := setLimits(matrix, point.x, point.y)
-	pointValue := matrix[point.x][point.y]
+func findNeighbours(heightmap *grid.Grid[int], point grid.Point) (result []grid.Point) {
+	result = []grid.Point{point}
+	pointValue := heightmap.Get(point)
 
-	if startX != point.x {
-		targetValue := matrix[startX][point.y]
-		if targetValue > pointValue && targetValue != 9 {
-			result = append(result, Point{x: startX, y: point.y})
-		}
-	}
-	if endX != point.x {
-		targetValue := matrix[endX][point.y]
-		if targetValue > pointValue && targetValue != 9 {
-			result = append(result, Point{x: endX, y: point.y})
-		}
-	}
-	if startY != point.y {
-		targetValue := matrix[point.x][startY]
+	for _, neighbour := range heightmap.Neighbours4(point) {
+		targetValue := heightmap.Get(neighbour)
 		if targetValue > pointValue && targetValue != 9 {
-			result = append(result, Point{x: point.x, y: startY})
-		}
-	}
-	if endY != point.y {
-		targetValue := matrix[point.x][endY]
-		if targetValue > pointValue && targetValue != 9 {
-			result = append(result, Point{x: point.x, y: endY})
+			result = append(result, neighbour)
 		}
 	}
 
@@ -128,9 +66,9 @@ func findNeighbours(matrix [][]int, point Point) (result []Point) {
 }
 
 // pointInBasin will simply return if a given Point exists in a provided slice of Point.
-func pointInBasin(basin []Point, point Point) bool {
+func pointInBasin(basin []grid.Point, point grid.Point) bool {
 	for _, current := range basin {
-		if current.x == point.x && current.y == point.y {
+		if current == point {
 			return true
 		}
 	}
@@ -138,9 +76,9 @@ func pointInBasin(basin []Point, point Point) bool {
 }
 
 // removeDuplicates removes all duplicated Point form a provided Point slice.
-func removeDuplicates(basin []Point) []Point {
-	collection := make(map[Point]int)
-	result := make([]Point, 0)
+func removeDuplicates(basin []grid.Point) []grid.Point {
+	collection := make(map[grid.Point]int)
+	result := make([]grid.Point, 0)
 
 	for i := 0; i < len(basin); i++ {
 		collection[basin[i]]++
@@ -155,8 +93,8 @@ func removeDuplicates(basin []Point) []Point {
 
 // recursiveBasinDetection will find out, for a given point, which new adjacent points belong to the basin
 // and recursively extend to the adjacent ones to those.
-func recursiveBasinDetection(matrix [][]int, basin []Point, point Point) []Point {
-	candidates := findNeighbours(matrix, point)
+func recursiveBasinDetection(heightmap *grid.Grid[int], basin []grid.Point, point grid.Point) []grid.Point {
+	candidates := findNeighbours(heightmap, point)
 
 	// We clean the candidates slice by dropping duplicates we already have in the basin.
 	for i := 0; i < len(candidates); i++ {
@@ -167,41 +105,32 @@ func recursiveBasinDetection(matrix [][]int, basin []Point, point Point) []Point
 	}
 
 	// For each potential candidate, we explore the basin in that direction.
-	tmpCandidates := make([]Point, len(candidates))
+	tmpCandidates := make([]grid.Point, len(candidates))
 	copy(tmpCandidates, candidates)
 	for i := 0; i < len(tmpCandidates); i++ {
 		candidate := tmpCandidates[i]
-		if candidate.x == point.x && candidate.y == point.y {
+		if candidate == point {
 			continue
 		}
-		candidates = removeDuplicates(append(candidates, recursiveBasinDetection(matrix, append(basin, candidates...), candidate)...))
+		candidates = removeDuplicates(append(candidates, recursiveBasinDetection(heightmap, append(basin, candidates...), candidate)...))
 	}
 
 	return candidates
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day09_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	matrix := loadMatrix(bufio.NewScanner(file))
-	lowPoints := make([]Point, 0)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	lowPoints := make([]grid.Point, 0)
 	basinSizes := make([]int, 0)
 	result := 1
 
-	for i := range matrix {
-		for q := range matrix[i] {
-			if validatePosition(matrix, i, q) {
-				lowPoints = append(lowPoints, Point{x: i, y: q})
-			}
+	for _, point := range p.heightmap.Points() {
+		if validatePosition(p.heightmap, point) {
+			lowPoints = append(lowPoints, point)
 		}
 	}
 
 	for i := range lowPoints {
-		basinTopography := recursiveBasinDetection(matrix, make([]Point, 0), lowPoints[i])
+		basinTopography := recursiveBasinDetection(p.heightmap, make([]grid.Point, 0), lowPoints[i])
 		basinSizes = append(basinSizes, len(basinTopography))
 	}
 
@@ -211,21 +140,28 @@ func secondExercise() (int, error) {
 		result *= basinSizes[i]
 	}
 
-	return result, nil
+	return solver.Number(result), nil
 }
 
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
+// Draw returns the heights of the heightmap for the first part, and for the second one the size of the basin each point
+// belongs to, which is 0 for the points outside basins.
+func (p *Puzzle) Draw(part int) (*grid.Grid[int], picture.Palette, error) {
+	switch part {
+	case 1:
+		return p.heightmap.Clone(), picture.Gray(0, 9), nil
+	case 2:
+		sizes := grid.New[int](p.heightmap.Width(), p.heightmap.Height())
+		for _, point := range p.heightmap.Points() {
+			if validatePosition(p.heightmap, point) {
+				basin := recursiveBasinDetection(p.heightmap, make([]grid.Point, 0), point)
+				for _, member := range basin {
+					sizes.Set(member, len(basin))
+				}
+			}
+		}
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
+		return sizes, picture.Heat(picture.Range(sizes)), nil
+	default:
+		return nil, nil, fmt.Errorf("invalid part %d, it must be 1 or 2", part)
 	}
 }
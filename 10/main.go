@@ -1,179 +1,72 @@
-package main
+package day10
 
 import (
-	"advent_2021/dataStructures"
+	"advent_2021/brackets"
 	"advent_2021/extra"
-	"bufio"
-	"container/heap"
+	"advent_2021/solver"
 	"errors"
-	"fmt"
-	"log"
-	"os"
+	"io"
 	"sort"
+	"strings"
 )
 
-// Defining the interface of a priority queue.
+func init() {
+	solver.Register(10, func() solver.Solver { return &Puzzle{} })
+}
 
-func loadInput(scanner *bufio.Scanner) (result []string) {
+// loadInput reads the lines of the navigation subsystem, making sure they are only made of chunk characters.
+func loadInput(scanner *extra.Scanner) (result []string, err error) {
 	for scanner.Scan() {
-		result = append(result, scanner.Text())
+		line := scanner.Line()
+		if index := strings.IndexFunc(line.Text, func(r rune) bool { return !strings.ContainsRune("()[]{}<>", r) }); index >= 0 {
+			return nil, line.Errorf(index, "invalid chunk character %q", line.Text[index])
+		}
+
+		result = append(result, line.Text)
 	}
 
-	return result
+	return result, nil
 }
 
-// invertCharacter will return the closing character for a given (, {, [ or <.
-func invertCharacter(c rune) (result rune, err error) {
-	switch c {
-	case '(':
-		result = ')'
-	case '[':
-		result = ']'
-	case '{':
-		result = '}'
-	case '<':
-		result = '>'
-	default:
-		return '0', errors.New("not matching character")
-	}
-	return result, nil
+// Puzzle holds the lines of the navigation subsystem.
+type Puzzle struct {
+	lines []string
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day10_exercise01.txt")
-	if err != nil {
-		return -1, err
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+	if p.lines, err = loadInput(scanner); err != nil {
+		return err
 	}
-	defer extra.CloseFile(file)
 
-	var points = map[rune]int{
-		')': 3,
-		']': 57,
-		'}': 1197,
-		'>': 25137,
-	}
+	return scanner.Err()
+}
 
-	input := loadInput(bufio.NewScanner(file))
-	scoreTable := map[rune]int{
-		')': 0,
-		']': 0,
-		'}': 0,
-		'>': 0,
-	}
+// PartOne adds the points of the first illegal character of each corrupted line.
+func (p *Puzzle) PartOne() (solver.Answer, error) {
 	score := 0
 
-	for line := range input {
-		rpq := make(dataStructures.RunePriorityQueue, 0)
-		heap.Init(&rpq)
-
-		for i, r := range input[line] {
-			if r == '(' || r == '[' || r == '{' || r == '<' {
-				inverted, err := invertCharacter(r)
-				if err != nil {
-					log.Fatal(err)
-				}
-				item := &dataStructures.Item{
-					Value:    inverted,
-					Priority: i,
-				}
-				heap.Push(&rpq, item)
-			} else {
-				required := heap.Pop(&rpq).(*dataStructures.Item)
-				// If the enclosing character does NOT match the previous one, we consider this line corrupted, annotate
-				// the score and keep going.
-				if r != required.Value {
-					scoreTable[r]++
-					break
-				}
-			}
-		}
+	for _, line := range p.lines {
+		score += brackets.Default.ErrorScore(brackets.Check(line))
 	}
 
-	for i := range scoreTable {
-		score += scoreTable[i] * points[i]
-	}
-
-	return score, nil
+	return solver.Number(score), nil
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day10_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	var points = map[rune]int{
-		')': 1,
-		']': 2,
-		'}': 3,
-		'>': 4,
-	}
-
-	input := loadInput(bufio.NewScanner(file))
+// PartTwo finds the median of the completion scores of the incomplete lines.
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
 	scoreTable := make([]int, 0)
 
-	for line := range input {
-		rpq := make(dataStructures.RunePriorityQueue, 0)
-		lineRunes := make([]rune, 0)
-		lineScore := 0
-		corrupted := false
-		heap.Init(&rpq)
-
-		for i, r := range input[line] {
-			// First we check the line, accumulate the symbols required and discard the corrupted ones.
-			if r == '(' || r == '[' || r == '{' || r == '<' {
-				inverted, err := invertCharacter(r)
-				if err != nil {
-					log.Fatal(err)
-				}
-				item := &dataStructures.Item{
-					Value:    inverted,
-					Priority: i,
-				}
-				heap.Push(&rpq, item)
-			} else {
-				required := heap.Pop(&rpq).(*dataStructures.Item)
-				// The line is corrupted if the required symbol does not match.
-				if r != required.Value {
-					corrupted = true
-					break
-				}
-			}
-		}
-		if !corrupted {
-			// Now we traverse the required symbol queue and store those we need to make valid lines.
-			for rpq.Len() > 0 {
-				item := heap.Pop(&rpq).(*dataStructures.Item)
-				lineRunes = append(lineRunes, item.Value)
-			}
-
-			// We calculate the score for this line using the second exercise rules.
-			for i := range lineRunes {
-				lineScore = lineScore*5 + points[lineRunes[i]]
-			}
-
-			scoreTable = append(scoreTable, lineScore)
+	for _, line := range p.lines {
+		if result := brackets.Check(line); result.Status == brackets.Incomplete {
+			scoreTable = append(scoreTable, brackets.Default.CompletionScore(result))
 		}
 	}
 
-	sort.Ints(scoreTable)
-
-	return scoreTable[len(scoreTable)/2], nil
-}
-
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
+	if len(scoreTable) == 0 {
+		return solver.Answer{}, errors.New("there are no incomplete lines")
 	}
+	sort.Ints(scoreTable)
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
-	}
+	return solver.Number(scoreTable[len(scoreTable)/2]), nil
 }
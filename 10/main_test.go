@@ -0,0 +1,23 @@
+package day10
+
+import (
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "26397", PartTwo: "288957"},
+		// Over-closed lines are neither corrupted nor incomplete, so they count for no part.
+		{Name: "over-closed", Input: "())\n<{\n", PartOne: "0", PartTwo: "19"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 10, func() solver.Solver { return &Puzzle{} }, example)
+}
@@ -1,173 +1,154 @@
-package main
+package day11
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"math"
-	"os"
 )
 
+func init() {
+	solver.Register(11, func() solver.Solver { return &Puzzle{} })
+}
+
 type Octopus struct {
 	energy  int
 	flashed bool
 }
 
-func loadInput(scanner *bufio.Scanner) [][]Octopus {
-	result := make([][]Octopus, 0)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		tmpSlice := make([]Octopus, len(line))
-		for i, val := range line {
-			tmpSlice[i] = Octopus{energy: extra.ConvertToInt(string(val)), flashed: false}
-		}
-
-		result = append(result, tmpSlice)
+// loadInput reads the grid of energy levels and wraps each of them into an Octopus.
+func loadInput(scanner *extra.Scanner) (*grid.Grid[Octopus], error) {
+	energies, err := grid.ReadDigits(scanner)
+	if err != nil {
+		return nil, err
 	}
 
-	return result
+	return grid.Map(energies, func(_ grid.Point, energy int) Octopus {
+		return Octopus{energy: energy, flashed: false}
+	}), nil
 }
 
-//restartFlashMemory sets to logical false all the Octopus on the board.
-func restartFlashMemory(matrix *[][]Octopus) {
-	for x := 0; x < len(*matrix); x++ {
-		for y := 0; y < len((*matrix)[x]); y++ {
-			(*matrix)[x][y].flashed = false
-		}
-	}
+// Puzzle holds the energy levels of the dumbo octopuses.
+type Puzzle struct {
+	octopuses *grid.Grid[Octopus]
 }
 
-// setLimits will return bounded values to traverse adjacent elements to a given position in the provided matrix, this
-// allows us to loop through these adjacent positions without the risk of going out of bounds.
-// NOTE: Copied form my solution to day 9.
-func setLimits(matrix *[][]Octopus, x int, y int) (int, int, int, int) {
-	startX, endX := x-1, x+1
-	if startX < 0 {
-		startX = x
-	}
-	if endX == len(*matrix) {
-		endX = x
-	}
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	p.octopuses, err = loadInput(extra.NewScanner(input))
+	return err
+}
 
-	startY, endY := y-1, y+1
-	if startY < 0 {
-		startY = y
-	}
-	if endY == len((*matrix)[0]) {
-		endY = y
-	}
-	return startX, endX, startY, endY
+// restartFlashMemory sets to logical false all the Octopus on the board.
+func restartFlashMemory(octopuses *grid.Grid[Octopus]) {
+	octopuses.Each(func(point grid.Point, octopus Octopus) {
+		octopus.flashed = false
+		octopuses.Set(point, octopus)
+	})
 }
 
-//splashFlash will increase the energy levels of all surrounding octopuses to a given location.
-func splashFlash(matrix *[][]Octopus, x, y int) {
-	startX, endX, startY, endY := setLimits(matrix, x, y)
-	for i := startX; i <= endX; i++ {
-		for q := startY; q <= endY; q++ {
-			// We only raise the energy level of a non flashed octopus.
-			if (*matrix)[i][q].flashed == false {
-				(*matrix)[i][q].energy++
-			}
+// splashFlash will increase the energy levels of all surrounding octopuses to a given location.
+func splashFlash(octopuses *grid.Grid[Octopus], point grid.Point) {
+	for _, neighbour := range octopuses.Neighbours8(point) {
+		// We only raise the energy level of a non flashed octopus.
+		if octopus := octopuses.Get(neighbour); octopus.flashed == false {
+			octopus.energy++
+			octopuses.Set(neighbour, octopus)
 		}
 	}
 }
 
-//increaseEnergy will iterate over the board and increase energy accordingly.
-func increaseEnergy(matrix *[][]Octopus) {
-	for x := 0; x < len(*matrix); x++ {
-		for y := 0; y < len((*matrix)[x]); y++ {
-			(*matrix)[x][y].energy++
-		}
-	}
+// increaseEnergy will iterate over the board and increase energy accordingly.
+func increaseEnergy(octopuses *grid.Grid[Octopus]) {
+	octopuses.Each(func(point grid.Point, octopus Octopus) {
+		octopus.energy++
+		octopuses.Set(point, octopus)
+	})
 }
 
-//triggerFlash goes over a given matrix of Octopus and trigger the flash on each one that has more than 9 energy and has
-//not flashed already.
-func triggerFlash(matrix *[][]Octopus) int {
+// triggerFlash goes over a given grid of Octopus and trigger the flash on each one that has more than 9 energy and has
+// not flashed already.
+func triggerFlash(octopuses *grid.Grid[Octopus]) int {
 	flashes := 0
-	triggered := make([][]int, 0)
-
-	for x := 0; x < len(*matrix); x++ {
-		for y := 0; y < len((*matrix)[x]); y++ {
-			currentOctopus := &(*matrix)[x][y]
-			if currentOctopus.energy > 9 && currentOctopus.flashed == false {
-				flashes++
-				currentOctopus.flashed = true
-				currentOctopus.energy = 0
-				triggered = append(triggered, []int{x, y})
-			}
+	triggered := make([]grid.Point, 0)
+
+	octopuses.Each(func(point grid.Point, octopus Octopus) {
+		if octopus.energy > 9 && octopus.flashed == false {
+			flashes++
+			octopuses.Set(point, Octopus{energy: 0, flashed: true})
+			triggered = append(triggered, point)
 		}
-	}
+	})
 
 	if flashes == 0 {
 		return flashes
 	} else {
 
-		for i := range triggered {
-			splashFlash(matrix, triggered[i][0], triggered[i][1])
+		for _, point := range triggered {
+			splashFlash(octopuses, point)
 		}
 
-		flashes += triggerFlash(matrix)
+		flashes += triggerFlash(octopuses)
 		return flashes
 	}
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day11_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	octopuses := loadInput(bufio.NewScanner(file))
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	octopuses := p.octopuses.Clone()
 	steps := 100
 	flashes := 0
 
 	for i := 0; i < steps; i++ {
-		increaseEnergy(&octopuses)
-		flashes += triggerFlash(&octopuses)
-		restartFlashMemory(&octopuses)
+		increaseEnergy(octopuses)
+		flashes += triggerFlash(octopuses)
+		restartFlashMemory(octopuses)
 	}
 
-	return flashes, nil
+	return solver.Number(flashes), nil
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day11_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	octopuses := loadInput(bufio.NewScanner(file))
-	numberOfOctopuses := len(octopuses) * len(octopuses[0])
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	octopuses := p.octopuses.Clone()
+	numberOfOctopuses := octopuses.Width() * octopuses.Height()
 
 	for i := 1; i < math.MaxInt; i++ {
-		increaseEnergy(&octopuses)
-		if triggerFlash(&octopuses) == numberOfOctopuses {
-			return i, nil
+		increaseEnergy(octopuses)
+		if triggerFlash(octopuses) == numberOfOctopuses {
+			return solver.Number(i), nil
 		}
-		restartFlashMemory(&octopuses)
+		restartFlashMemory(octopuses)
 	}
 
-	return -1, errors.New("no solution found")
+	return solver.Answer{}, errors.New("no solution found")
 }
 
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
+// Draw returns how many times each octopus flashes during the steps of a part: the first 100 for the first part, and up
+// to the first one when they all flash at once for the second part.
+func (p *Puzzle) Draw(part int) (*grid.Grid[int], picture.Palette, error) {
+	if part != 1 && part != 2 {
+		return nil, nil, fmt.Errorf("invalid part %d, it must be 1 or 2", part)
 	}
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
+	octopuses := p.octopuses.Clone()
+	flashes := grid.New[int](octopuses.Width(), octopuses.Height())
+
+	for step := 1; step < math.MaxInt; step++ {
+		increaseEnergy(octopuses)
+		synchronised := triggerFlash(octopuses) == octopuses.Width()*octopuses.Height()
+		octopuses.Each(func(point grid.Point, octopus Octopus) {
+			if octopus.flashed {
+				flashes.Set(point, flashes.Get(point)+1)
+			}
+		})
+		restartFlashMemory(octopuses)
+
+		if (part == 1 && step == 100) || (part == 2 && synchronised) {
+			break
+		}
 	}
+
+	return flashes, picture.Heat(picture.Range(flashes)), nil
 }
@@ -0,0 +1,53 @@
+package day11
+
+import (
+	"advent_2021/extra"
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "1656", PartTwo: "195"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 11, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+func TestTriggerFlash(t *testing.T) {
+	octopuses, err := loadInput(extra.NewScanner(strings.NewReader("11111\n19991\n19191\n19991\n11111\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	increaseEnergy(octopuses)
+	if flashes := triggerFlash(octopuses); flashes != 9 {
+		t.Errorf("triggerFlash() = %d, want 9", flashes)
+	}
+
+	want := [][]int{
+		{3, 4, 5, 4, 3},
+		{4, 0, 0, 0, 4},
+		{5, 0, 0, 0, 5},
+		{4, 0, 0, 0, 4},
+		{3, 4, 5, 4, 3},
+	}
+	got := make([][]int, octopuses.Height())
+	for y := range got {
+		for _, octopus := range octopuses.Row(y) {
+			got[y] = append(got[y], octopus.energy)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("energy after the step = %v, want %v", got, want)
+	}
+}
@@ -1,147 +1,258 @@
-package main
+package day12
 
 import (
 	"advent_2021/extra"
-	"bufio"
-	"fmt"
-	"log"
-	"os"
-	"regexp"
+	"advent_2021/graph"
+	"advent_2021/solver"
+	"io"
 	"strings"
 )
 
+func init() {
+	solver.Register(12, func() solver.Solver { return &Puzzle{} })
+}
+
+// Kind classifies the caves by the rules about visiting them.
+type Kind int
+
+const (
+	Small Kind = iota
+	Large
+	Start
+	End
+)
+
+// caveKind tells the kind of a cave from its name: large caves are written in uppercase and small ones in lowercase.
+func caveKind(name string) Kind {
+	switch {
+	case name == "start":
+		return Start
+	case name == "end":
+		return End
+	case strings.ToUpper(name) == name:
+		return Large
+	default:
+		return Small
+	}
+}
+
+// Caves is the cave system, where each cave is a node of the graph.
+type Caves struct {
+	*graph.Graph[Kind]
+	start, end graph.NodeID
+	// largeLinks are the connections between two large caves, which only allow a finite number of paths when visits to
+	// large caves are limited.
+	largeLinks []largeLink
+}
+
+// largeLink is a connection between two large caves, with the error reporting it where it was found in the input.
+type largeLink struct {
+	caves [2]graph.NodeID
+	err   error
+}
+
 // loadInput loads the puzzle input.  We will for simplicity avoid registering paths to the "start" node, as for no
-// possible path we would like to visit that node.
-func loadInput(scanner *bufio.Scanner) map[string][]string {
-	result := make(map[string][]string)
+// possible path we would like to visit that node.  Two connected large caves, or a large cave connected to itself, are
+// kept aside, as a path could go back and forth between them forever unless the rules limit visits to large caves.
+func loadInput(scanner *extra.Scanner) (*Caves, error) {
+	caves := &Caves{Graph: graph.New[Kind]()}
 
 	for scanner.Scan() {
-		tmpLine := strings.Split(scanner.Text(), "-")
-		leftNode := tmpLine[0]
-		rightNode := tmpLine[1]
+		left, right, err := scanner.Line().Cut("-")
+		if err != nil {
+			return nil, err
+		}
 
-		result[leftNode] = append(result[leftNode], rightNode)
-		result[rightNode] = append(result[rightNode], leftNode)
-	}
+		var ids [2]graph.NodeID
+		for i, cave := range []extra.Line{left, right} {
+			if cave.Text == "" {
+				return nil, cave.Errorf(0, "a connection needs two caves")
+			}
+			if strings.ToUpper(cave.Text) != cave.Text && strings.ToLower(cave.Text) != cave.Text {
+				return nil, cave.Errorf(0, "cave %q is neither large nor small", cave.Text)
+			}
+			ids[i] = caves.Intern(cave.Text, caveKind)
+		}
 
-	for key, connections := range result {
-		tmpCon := make([]string, 0)
-		tmpMap := make(map[string]bool)
+		if caves.Attr(ids[0]) == Large && caves.Attr(ids[1]) == Large {
+			caves.largeLinks = append(caves.largeLinks, largeLink{caves: ids, err: scanner.Line().Errorf(0,
+				"connecting large caves %s and %s allows endless paths", left.Text, right.Text)})
+		}
 
-		for _, conn := range connections {
-			if _, ok := tmpMap[conn]; !ok && conn != "start" {
-				tmpMap[conn] = true
-				tmpCon = append(tmpCon, conn)
+		for i, from := range ids {
+			if to := ids[1-i]; caves.Attr(to) != Start {
+				caves.AddArc(from, to)
 			}
 		}
+	}
 
-		result[key] = tmpCon
+	var ok bool
+	if caves.start, ok = caves.ID("start"); !ok {
+		return nil, scanner.Errorf("there is no start cave")
+	}
+	if caves.end, ok = caves.ID("end"); !ok {
+		return nil, scanner.Errorf("there is no end cave")
 	}
-	return result
+
+	return caves, nil
 }
 
-var largeCave, _ = regexp.Compile("[A-Z]+")
-var smallCave, _ = regexp.Compile("[a-z]+")
+// Counting chooses how paths are counted.  Both ways give the same results, so each of them can be used to check the
+// other one.
+type Counting int
 
-//existsInSlice simply lets us know if a value exists in a slice.
-func existsInSlice(value string, slice []string) (result bool) {
-	for _, elem := range slice {
-		if value == elem {
-			return true
-		}
+const (
+	// Memoise counts the paths from each state of the search only once, which is much faster on large cave systems.
+	// Rules it cannot keep track of, with more than 64 limited caves or caves visited more than twice, are enumerated.
+	Memoise Counting = iota
+	// Enumerate goes through every path one by one.
+	Enumerate
+)
+
+func (c Counting) String() string {
+	if c == Enumerate {
+		return "enumerate"
 	}
 
-	return result
+	return "memoise"
 }
 
-// firstSolution will return all the possible paths from a given path, in which no small cave (lowercase letter node) is
-// visited more than once.
-func firstSolution(data map[string][]string, path []string) (solution int) {
-	for _, value := range data[path[len(path)-1]] {
-		if largeCave.MatchString(value) || !existsInSlice(value, path) {
-			if value == "end" {
-				solution += 1
-			} else {
-				tmp := make([]string, len(path))
-				copy(tmp, path)
-
-				tmp = append(tmp, value)
-				solution += firstSolution(data, tmp)
-			}
-		}
+// Puzzle holds the connections between the caves.
+type Puzzle struct {
+	caves *Caves
+	// Counting is the way paths are counted, memoising them by default.
+	Counting Counting
+}
+
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+	if p.caves, err = loadInput(scanner); err != nil {
+		return err
 	}
 
-	return solution
+	return scanner.Err()
 }
 
-// secondSolution will return all the possible paths from a given path, allowing to repeat small caves once per path. If
-// we try to append a small cave to the path we already visited, we complete the path from that point using the result
-// from firstSolution.
-func secondSolution(data map[string][]string, path []string) (solution int) {
-	for _, value := range data[path[len(path)-1]] {
-		if value == "end" {
-			solution += 1
-		} else {
-			tmp := make([]string, len(path))
-			copy(tmp, path)
-
-			tmp = append(tmp, value)
-
-			a := smallCave.MatchString(value)
-			b := existsInSlice(value, path)
-
-			if a && b {
-				solution += firstSolution(data, tmp)
-			} else {
-				solution += secondSolution(data, tmp)
-			}
+// CountPaths returns the number of paths from the start to the end allowed by the rules.
+func (p *Puzzle) CountPaths(rules Rules) (int, error) {
+	limits, err := p.caves.limits(rules)
+	if err != nil {
+		return 0, err
+	}
 
+	if p.Counting == Memoise {
+		if count, ok := p.caves.memoisePaths(limits, rules.Revisits); ok {
+			return count, nil
 		}
+		solver.Debugf("The rules %+v cannot be memoised, enumerating the paths instead.\n", rules)
 	}
 
-	return solution
+	return p.caves.enumeratePaths(limits, rules.Revisits), nil
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day12_exercise01.txt")
-	if err != nil {
-		return -1, err
+// enumeratePaths counts the paths allowed by some limits walking through each of them.
+func (c *Caves) enumeratePaths(limits []limit, spares int) int {
+	return c.AllPaths(c.start, c.end, quota(limits, spares), nil)
+}
+
+// visitState is the state of a path that matters to know how it can go on: the cave where it is, the sets of caves
+// visited so far and of those visited twice, with a bit for each cave, and the number of spare visits left.
+type visitState struct {
+	cave    graph.NodeID
+	visited uint64
+	twice   uint64
+	spares  int
+}
+
+// memoisePaths counts the paths allowed by some limits remembering how many there are from each visitState, as every
+// path reaching the same state can go on in the same ways.  It needs a bit for each cave with limited visits, so there
+// can be at most 64, and it only works when they can be visited up to twice, otherwise it returns false.
+func (c *Caves) memoisePaths(limits []limit, spares int) (int, bool) {
+	bits := make([]uint64, c.Len())
+	tracked := 0
+	for id, l := range limits {
+		if l.visits < 0 {
+			continue
+		}
+		if l.visits > 2 || l.extended > 2 || tracked == 64 {
+			return 0, false
+		}
+		bits[id] = 1 << tracked
+		tracked++
 	}
-	defer extra.CloseFile(file)
 
-	graph := loadInput(bufio.NewScanner(file))
+	memo := make(map[visitState]int)
+	var count func(state visitState) int
+	count = func(state visitState) (result int) {
+		if state.cave == c.end {
+			return 1
+		}
+		if result, ok := memo[state]; ok {
+			return result
+		}
+
+		for _, next := range c.Neighbours(state.cave) {
+			nextState := visitState{cave: next, visited: state.visited, twice: state.twice, spares: state.spares}
 
-	fmt.Println(graph)
+			if bit := bits[next]; bit != 0 {
+				visits := 0
+				if state.visited&bit != 0 {
+					visits++
+				}
+				if state.twice&bit != 0 {
+					visits++
+				}
 
-	return firstSolution(graph, []string{"start"}), nil
-}
+				// The same decision as graph.Quota makes, on the visits kept in the bits.
+				switch l := limits[next]; {
+				case visits < l.visits:
+				case visits >= l.extended:
+					continue
+				case visits > l.visits:
+				case state.spares > 0:
+					nextState.spares--
+				default:
+					continue
+				}
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day12_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
+				if visits == 0 {
+					nextState.visited |= bit
+				} else {
+					nextState.twice |= bit
+				}
+			}
 
-	graph := loadInput(bufio.NewScanner(file))
+			result += count(nextState)
+		}
 
-	fmt.Println(graph)
+		memo[state] = result
+		return result
+	}
 
-	return secondSolution(graph, []string{"start"}), nil
+	if limits[c.start].visits == 0 {
+		return 0, true
+	}
+	return count(visitState{cave: c.start, visited: bits[c.start], spares: spares}), true
 }
 
-func main() {
-	firstResult, err := firstExercise()
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	solver.Debugf("%v", p.caves)
+
+	count, err := p.CountPaths(PartOneRules)
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
+		return solver.Answer{}, err
 	}
 
-	secondResult, err := secondExercise()
+	return solver.Number(count), nil
+}
+
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	solver.Debugf("%v", p.caves)
+
+	count, err := p.CountPaths(PartTwoRules)
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
+		return solver.Answer{}, err
 	}
+
+	return solver.Number(count), nil
 }
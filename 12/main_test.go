@@ -0,0 +1,328 @@
+package day12
+
+import (
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+const largerExample = `dc-end
+HN-start
+start-kj
+dc-start
+dc-HN
+LN-dc
+HN-end
+kj-sa
+kj-HN
+kj-dc
+`
+
+const evenLargerExample = `fs-end
+he-DX
+fs-he
+start-DX
+pj-DX
+end-zg
+zg-sl
+zg-pj
+pj-he
+RW-he
+fs-DX
+pj-RW
+zg-RW
+start-pj
+he-WI
+zg-he
+pj-fs
+start-RW
+`
+
+// generateLadder returns a cave system with a chain of small caves from the start to the end, where each pair of
+// consecutive small caves is also connected through a large cave.  There are 2^(smallCaves-1) paths for the first
+// part, but the visited small caves are almost always a prefix of the chain, which is the best case for memoising.
+func generateLadder(smallCaves int) string {
+	var result strings.Builder
+	fmt.Fprintf(&result, "start-c0\n")
+	for i := 0; i+1 < smallCaves; i++ {
+		fmt.Fprintf(&result, "c%d-c%d\nc%d-L%d\nL%d-c%d\n", i, i+1, i, i, i, i+1)
+	}
+	fmt.Fprintf(&result, "c%d-end\n", smallCaves-1)
+
+	return result.String()
+}
+
+func TestPuzzle(t *testing.T) {
+	cases := []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "10", PartTwo: "36"},
+		{Name: "larger example", Input: largerExample, PartOne: "19", PartTwo: "103"},
+		{Name: "even larger example", Input: evenLargerExample, PartOne: "226", PartTwo: "3509"},
+		{Name: "ladder", Input: generateLadder(8), PartOne: "128", PartTwo: "1920"},
+	}
+
+	for _, counting := range []Counting{Memoise, Enumerate} {
+		t.Run(fmt.Sprint(counting), func(t *testing.T) {
+			solvertest.Run(t, func() solver.Solver { return &Puzzle{Counting: counting} }, cases)
+		})
+	}
+}
+
+func TestCountingCrossCheck(t *testing.T) {
+	rules := []Rules{
+		PartOneRules,
+		PartTwoRules,
+		{SmallVisits: 1, Revisits: 2, RevisitVisits: 2},
+		{SmallVisits: 2},
+		{SmallVisits: 0, Revisits: 3, RevisitVisits: 1},
+		{SmallVisits: 1, Revisits: 1, RevisitVisits: 2, LargeVisits: 1},
+		{SmallVisits: 1, LargeVisits: 2, Forbidden: []string{"c1"}},
+	}
+
+	for _, input := range []string{example, largerExample, generateLadder(2), generateLadder(6)} {
+		p := &Puzzle{}
+		if err := p.Parse(strings.NewReader(input)); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range rules {
+			if _, ok := p.caves.ID("c1"); len(r.Forbidden) > 0 && !ok {
+				continue
+			}
+
+			p.Counting = Memoise
+			memoised, err := p.CountPaths(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.Counting = Enumerate
+			if enumerated, _ := p.CountPaths(r); memoised != enumerated {
+				t.Errorf("%d caves with rules %+v: memoised %d paths, enumerated %d", p.caves.Len(), r, memoised,
+					enumerated)
+			}
+		}
+	}
+}
+
+func TestCountPaths(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		rules Rules
+		want  int
+	}{
+		{"part one", PartOneRules, 10},
+		{"part two", PartTwoRules, 36},
+		{"forbidden", Rules{SmallVisits: 1, Forbidden: []string{"A"}}, 1},
+		// start,A,end, start,b,end, start,A,b,end and start,b,A,end.
+		{"large caves once", Rules{SmallVisits: 1, LargeVisits: 1}, 4},
+		// The same four paths, and start,A,b,A,end and start,A,c,A,end, visiting a single small cave.
+		{"a single small cave", Rules{Revisits: 1, RevisitVisits: 1}, 6},
+		{"forbidden start", Rules{SmallVisits: 1, Forbidden: []string{"start"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := p.CountPaths(tt.rules); err != nil || got != tt.want {
+				t.Errorf("CountPaths() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+
+	for _, rules := range []Rules{
+		{SmallVisits: -1},
+		{SmallVisits: 2, Revisits: 1, RevisitVisits: 2},
+		{SmallVisits: 1, Forbidden: []string{"z"}},
+	} {
+		if _, err := p.CountPaths(rules); err == nil {
+			t.Errorf("CountPaths() accepted the rules %+v", rules)
+		}
+	}
+
+	// Small caves visited thrice cannot be memoised, so they are enumerated either way.
+	enumerated := &Puzzle{caves: p.caves, Counting: Enumerate}
+	want, err := enumerated.CountPaths(Rules{SmallVisits: 3})
+	if err != nil || want <= 36 {
+		t.Fatalf("CountPaths() enumerating small caves visited thrice = %d, %v", want, err)
+	}
+	if got, err := p.CountPaths(Rules{SmallVisits: 3}); err != nil || got != want {
+		t.Errorf("CountPaths() of small caves visited thrice = %d, %v, want %d", got, err, want)
+	}
+}
+
+func TestWritePaths(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		rules  Rules
+		filter PathFilter
+		limit  int
+		want   string
+	}{
+		{"short paths", PartOneRules, PathFilter{MaxLength: 3}, 0, "start,A,end\nstart,b,end\n"},
+		{"long paths", PartOneRules, PathFilter{MinLength: 7}, 0, "start,A,c,A,b,A,end\nstart,A,b,A,c,A,end\n"},
+		{"through", PartTwoRules, PathFilter{Through: []string{"c", "d"}}, 0, "start,A,c,A,b,d,b,A,end\nstart,A,c,A,b,d,b,end\n" +
+			"start,A,b,d,b,A,c,A,end\nstart,b,d,b,A,c,A,end\n"},
+		{"avoid", PartOneRules, PathFilter{Avoid: []string{"A"}}, 0, "start,b,end\n"},
+		{"limit", PartTwoRules, PathFilter{}, 2, "start,A,c,A,c,A,b,A,end\nstart,A,c,A,c,A,b,end\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			written, err := p.WritePaths(&output, tt.rules, tt.filter, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if output.String() != tt.want || written != strings.Count(tt.want, "\n") {
+				t.Errorf("WritePaths() = %d paths\n%swant\n%s", written, output.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := p.WritePaths(&strings.Builder{}, PartOneRules, PathFilter{Through: []string{"z"}}, 0); err == nil {
+		t.Error("WritePaths() accepted an unknown cave")
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader("start-A\nA-b\nb-end\nstart-b\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	if err := p.WriteDOT(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `graph caves {
+	"start" [shape=doublecircle, style=filled, fillcolor=palegreen];
+	"A" [shape=box, style=filled, fillcolor=lightblue];
+	"b" [shape=ellipse];
+	"end" [shape=doublecircle, style=filled, fillcolor=lightcoral];
+	"start" -- "A";
+	"start" -- "b";
+	"A" -- "b";
+	"b" -- "end";
+}
+`
+	if output.String() != want {
+		t.Errorf("WriteDOT() =\n%swant\n%s", output.String(), want)
+	}
+}
+
+func TestMemoiseTooManySmallCaves(t *testing.T) {
+	// A single path through 65 small caves, too many to memoise but quick to enumerate.
+	var input strings.Builder
+	fmt.Fprintf(&input, "start-c0\n")
+	for i := 0; i < 64; i++ {
+		fmt.Fprintf(&input, "c%d-c%d\n", i, i+1)
+	}
+	fmt.Fprintf(&input, "c64-end\n")
+
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(input.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := p.PartOne(); err != nil || got.String() != "1" {
+		t.Errorf("PartOne() through 65 small caves = %v, %v, want 1", got, err)
+	}
+}
+
+func TestConnectedLargeCaves(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+		rules Rules
+		want  int
+	}{
+		// start,A,B,end and start,A,b,B,end.
+		{"large caves", "start-A\nA-b\nb-B\nB-A\nB-end\n",
+			"input:4:1: connecting large caves B and A allows endless paths", Rules{SmallVisits: 1, LargeVisits: 1}, 2},
+		// start,A,end and start,A,A,end.
+		{"large loop", "start-A\nA-A\nA-end\n", "input:2:1: connecting large caves A and A allows endless paths",
+			Rules{SmallVisits: 1, LargeVisits: 2}, 2},
+		// Without B, start,A,c,end and start,A,b,A,c,end are left.
+		{"forbidden large cave", "start-A\nA-b\nb-A\nA-B\nB-A\nA-c\nc-end\n",
+			"input:4:1: connecting large caves A and B allows endless paths",
+			Rules{SmallVisits: 1, Forbidden: []string{"B"}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Puzzle{}
+			if err := p.Parse(strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.PartOne(); err == nil || err.Error() != tt.err {
+				t.Errorf("PartOne() error = %v, want %s", err, tt.err)
+			}
+
+			for _, counting := range []Counting{Memoise, Enumerate} {
+				p.Counting = counting
+				if got, err := p.CountPaths(tt.rules); err != nil || got != tt.want {
+					t.Errorf("%v CountPaths() = %d, %v, want %d", counting, got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no start", "a-end\n", "input:1:1: there is no start cave"},
+		{"no end", "start-a\n", "input:1:1: there is no end cave"},
+		{"mixed case", "start-Ab\nAb-end\n", "input:1:7: cave \"Ab\" is neither large nor small"},
+		{"missing cave", "start-\n", "input:1:7: a connection needs two caves"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Puzzle{}).Parse(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 12, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+// BenchmarkCounting compares both ways of counting on generated cave systems.  Enumerating doubles its time with each
+// small cave, so it is only measured on the smaller ones.
+func BenchmarkCounting(b *testing.B) {
+	for _, smallCaves := range []int{8, 16, 24, 40} {
+		for _, counting := range []Counting{Memoise, Enumerate} {
+			if counting == Enumerate && smallCaves > 16 {
+				continue
+			}
+
+			input := []byte(generateLadder(smallCaves))
+			factory := func() solver.Solver { return &Puzzle{Counting: counting} }
+			b.Run(fmt.Sprintf("%v/%d small caves", counting, smallCaves), solver.PartBenchmark(factory, input, 1))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package day12
+
+import (
+	"advent_2021/graph"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PathFilter selects which paths are listed.  Lengths count the caves of a path, start and end included, and are not
+// checked when they are 0.
+type PathFilter struct {
+	MinLength int
+	MaxLength int
+	// Through are caves every path must visit.
+	Through []string
+	// Avoid are caves no path may visit.
+	Avoid []string
+}
+
+// filterRule wraps the rules of the visits, refusing the avoided caves and the paths longer than the maximum length.
+type filterRule struct {
+	graph.Rule
+	avoid     []bool
+	maxLength int
+	length    int
+}
+
+func (r *filterRule) Enter(id graph.NodeID) bool {
+	if r.avoid[id] || (r.maxLength > 0 && r.length == r.maxLength) || !r.Rule.Enter(id) {
+		return false
+	}
+
+	r.length++
+	return true
+}
+
+func (r *filterRule) Leave(id graph.NodeID) {
+	r.Rule.Leave(id)
+	r.length--
+}
+
+// caveIDs returns the IDs of the caves with the given names, failing if any of them does not exist.
+func (c *Caves) caveIDs(names []string) ([]graph.NodeID, error) {
+	result := make([]graph.NodeID, len(names))
+	for i, name := range names {
+		id, ok := c.ID(name)
+		if !ok {
+			return nil, fmt.Errorf("there is no cave %q", name)
+		}
+		result[i] = id
+	}
+
+	return result, nil
+}
+
+// WritePaths writes the paths allowed by some rules and selected by a filter, one per line like `start,A,b,end`, as
+// they are found.  It stops after limit paths, unless limit is 0, and returns how many paths were written.
+func (p *Puzzle) WritePaths(w io.Writer, rules Rules, filter PathFilter, limit int) (written int, err error) {
+	limits, err := p.caves.limits(rules)
+	if err != nil {
+		return 0, err
+	}
+	avoided, err := p.caves.caveIDs(filter.Avoid)
+	if err != nil {
+		return 0, err
+	}
+	through, err := p.caves.caveIDs(filter.Through)
+	if err != nil {
+		return 0, err
+	}
+
+	rule := &filterRule{
+		Rule:      quota(limits, rules.Revisits),
+		avoid:     make([]bool, p.caves.Len()),
+		maxLength: filter.MaxLength,
+	}
+	for _, id := range avoided {
+		rule.avoid[id] = true
+	}
+
+	output := bufio.NewWriter(w)
+	names := make([]string, 0)
+	p.caves.AllPaths(p.caves.start, p.caves.end, rule, func(path []graph.NodeID) bool {
+		if len(path) < filter.MinLength || !visitsAll(path, through) {
+			return true
+		}
+
+		names = names[:0]
+		for _, id := range path {
+			names = append(names, p.caves.Name(id))
+		}
+		if _, err = fmt.Fprintln(output, strings.Join(names, ",")); err != nil {
+			return false
+		}
+
+		written++
+		return limit == 0 || written < limit
+	})
+	if err != nil {
+		return written, err
+	}
+
+	return written, output.Flush()
+}
+
+// visitsAll reports whether a path goes through every one of the given caves.
+func visitsAll(path []graph.NodeID, caves []graph.NodeID) bool {
+	for _, cave := range caves {
+		found := false
+		for _, id := range path {
+			if id == cave {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dotStyles are the Graphviz attributes of each kind of cave.
+var dotStyles = map[Kind]string{
+	Start: `shape=doublecircle, style=filled, fillcolor=palegreen`,
+	End:   `shape=doublecircle, style=filled, fillcolor=lightcoral`,
+	Large: `shape=box, style=filled, fillcolor=lightblue`,
+	Small: `shape=ellipse`,
+}
+
+// WriteDOT writes the cave system as an undirected Graphviz graph, where the start, the end, large caves and small
+// caves are drawn differently.
+func (p *Puzzle) WriteDOT(w io.Writer) error {
+	output := bufio.NewWriter(w)
+	fmt.Fprintln(output, "graph caves {")
+
+	for id := 0; id < p.caves.Len(); id++ {
+		cave := graph.NodeID(id)
+		fmt.Fprintf(output, "\t%q [%s];\n", p.caves.Name(cave), dotStyles[p.caves.Attr(cave)])
+	}
+
+	// Connections are stored in both directions, except for those leading to the start, but each of them is drawn once.
+	for id := 0; id < p.caves.Len(); id++ {
+		from := graph.NodeID(id)
+		for _, to := range p.caves.Neighbours(from) {
+			if from < to || p.caves.Attr(from) == Start {
+				fmt.Fprintf(output, "\t%q -- %q;\n", p.caves.Name(from), p.caves.Name(to))
+			}
+		}
+	}
+
+	fmt.Fprintln(output, "}")
+	return output.Flush()
+}
@@ -0,0 +1,94 @@
+package day12
+
+import (
+	"advent_2021/graph"
+	"fmt"
+)
+
+// Rules describes how many times a path may visit each class of caves.  The start and the end are always visited
+// once.
+type Rules struct {
+	// SmallVisits is the number of times each small cave can be visited.
+	SmallVisits int
+	// Revisits is the number of small caves which may be visited more than SmallVisits times, up to RevisitVisits.
+	Revisits      int
+	RevisitVisits int
+	// LargeVisits is the number of times each large cave can be visited, where 0 means no limit.
+	LargeVisits int
+	// Forbidden are caves which can never be visited.
+	Forbidden []string
+}
+
+var (
+	// PartOneRules visit each small cave once.
+	PartOneRules = Rules{SmallVisits: 1}
+	// PartTwoRules also let a single small cave be visited twice.
+	PartTwoRules = Rules{SmallVisits: 1, Revisits: 1, RevisitVisits: 2}
+)
+
+// validate makes sure the rules make sense.
+func (r Rules) validate() error {
+	switch {
+	case r.SmallVisits < 0 || r.Revisits < 0 || r.LargeVisits < 0:
+		return fmt.Errorf("the number of visits cannot be negative")
+	case r.Revisits > 0 && r.RevisitVisits <= r.SmallVisits:
+		return fmt.Errorf("revisited small caves must allow more than %d visits, got %d", r.SmallVisits, r.RevisitVisits)
+	default:
+		return nil
+	}
+}
+
+// limit is the maximum number of visits of a cave, negative for no limit, and the number of visits a spare extends it
+// to, as used by graph.Quota.
+type limit struct {
+	visits   int
+	extended int
+}
+
+// limits returns the limit of each cave under some rules.  Connected large caves are rejected when both of them can be
+// visited without limit, as there would be endless paths.
+func (c *Caves) limits(rules Rules) ([]limit, error) {
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+	forbidden, err := c.caveIDs(rules.Forbidden)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]limit, c.Len())
+	for id := range result {
+		switch c.Attr(graph.NodeID(id)) {
+		case Small:
+			result[id] = limit{visits: rules.SmallVisits, extended: rules.SmallVisits}
+			if rules.Revisits > 0 {
+				result[id].extended = rules.RevisitVisits
+			}
+		case Large:
+			result[id] = limit{visits: -1, extended: -1}
+			if rules.LargeVisits > 0 {
+				result[id] = limit{visits: rules.LargeVisits, extended: rules.LargeVisits}
+			}
+		default:
+			result[id] = limit{visits: 1, extended: 1}
+		}
+	}
+
+	for _, id := range forbidden {
+		result[id] = limit{}
+	}
+	for _, link := range c.largeLinks {
+		if result[link.caves[0]].visits < 0 && result[link.caves[1]].visits < 0 {
+			return nil, link.err
+		}
+	}
+
+	return result, nil
+}
+
+// quota returns the rule walking through the caves within some limits.
+func quota(limits []limit, spares int) *graph.Quota {
+	return graph.NewQuota(len(limits), func(id graph.NodeID) (int, int) {
+		return limits[id].visits, limits[id].extended
+	}, spares)
+}
@@ -1,203 +1,286 @@
-package main
+package day13
 
 import (
 	"advent_2021/extra"
-	"bufio"
+	"advent_2021/grid"
+	"advent_2021/ocr"
+	"advent_2021/picture"
+	"advent_2021/solver"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io"
 	"strings"
 )
 
-type Paper struct {
-	matrix [][]int
+func init() {
+	solver.Register(13, func() solver.Solver { return &Puzzle{} })
 }
 
-func (p *Paper) countDots() (result int) {
-	for _, row := range p.matrix {
-		for _, val := range row {
-			if val > 0 {
-				result++
-			}
-		}
-	}
+// Paper is the sheet of transparent paper, kept as the set of its dots together with its size, so that it takes memory
+// only for the dots however far apart they are.
+type Paper struct {
+	dots          map[grid.Point]struct{}
+	width, height int
+}
 
-	return result
+// newPaper returns a blank paper of the given size.
+func newPaper(width, height int) *Paper {
+	return &Paper{dots: make(map[grid.Point]struct{}), width: width, height: height}
 }
 
-func (p *Paper) printPaper() {
-	fmt.Printf("\n")
-	for _, row := range p.matrix {
-		for _, value := range row {
-			character := "."
-			if value > 0 {
-				character = "#"
-			}
+// addDot marks a dot on the paper, which grows to hold it if needed.
+func (p *Paper) addDot(dot grid.Point) {
+	p.dots[dot] = struct{}{}
 
-			fmt.Printf("%s ", character)
-		}
-		fmt.Printf("\n")
+	if dot.X >= p.width {
+		p.width = dot.X + 1
+	}
+	if dot.Y >= p.height {
+		p.height = dot.Y + 1
 	}
 }
 
-func (p *Paper) foldOverY(position int) {
-	tmp := make([][]int, len(p.matrix[:position]))
-	for i := range tmp {
-		tmp[i] = make([]int, len(p.matrix[0]))
-	}
+func (p *Paper) countDots() int {
+	return len(p.dots)
+}
 
-	// Upper part of the fold
-	for i := 0; i < position; i++ {
-		for q := 0; q < len(p.matrix[0]); q++ {
-			tmp[i][q] += p.matrix[i][q]
-		}
+// dense returns the paper as a grid, where true marks a dot.
+func (p *Paper) dense() *grid.Grid[bool] {
+	result := grid.New[bool](p.width, p.height)
+	for dot := range p.dots {
+		result.Set(dot, true)
 	}
 
-	// Lower part of the fold
-	for i := 0; (i + position + 1) < len(p.matrix); i++ {
-		for q := 0; q < len(p.matrix[0]); q++ {
-			foldedDelta := position - 1 - i
-			toFoldDelta := i + position + 1
+	return result
+}
 
-			tmp[foldedDelta][q] += p.matrix[toFoldDelta][q]
+func (p *Paper) printPaper(w io.Writer) {
+	fmt.Fprintf(w, "\n")
+	p.dense().Print(w, func(dot bool) string {
+		if dot {
+			return "# "
 		}
+		return ". "
+	})
+}
+
+// foldedSize returns the size of the paper along the axis of a fold at a position, which is that of its larger side.
+// When the fold is past the end of the paper, the paper still reaches up to the fold.
+func foldedSize(size, position int) int {
+	if folded := size - 1 - position; folded > position {
+		return folded
 	}
 
-	p.matrix = tmp
+	return position
 }
 
-// Broken
-func (p *Paper) foldOverX(position int) {
-	tmp := make([][]int, len(p.matrix))
-	for i := range tmp {
-		tmp[i] = make([]int, position)
+// mirror returns where a coordinate ends up after folding at a position a paper of the given folded size.  The side
+// before the fold is moved so that it ends right before the fold, which only matters when the folded side is larger,
+// and the line of the fold disappears.
+func mirror(coordinate, position, size int) (int, bool) {
+	offset := size - position
+
+	switch {
+	case coordinate < position:
+		return coordinate + offset, true
+	case coordinate > position:
+		return 2*position - coordinate + offset, true
+	default:
+		return 0, false
 	}
+}
 
-	// Left part of the fold.
-	for i := range tmp {
-		for q := 0; q < position; q++ {
-			tmp[i][q] += p.matrix[i][q]
+// fold moves every dot to the point given by move on a new paper of the given size.  Dots moved to the same point
+// become a single one.
+func (p *Paper) fold(width, height int, move func(dot grid.Point) (grid.Point, bool)) {
+	folded := newPaper(width, height)
+	for dot := range p.dots {
+		if target, ok := move(dot); ok {
+			folded.addDot(target)
 		}
 	}
 
-	// Right part of the fold.
-	for i := range tmp {
-		for q := 0; (q + position + 1) < len(p.matrix[0]); q++ {
-			foldedDelta := position - 1 - q
-			toFoldDelta := q + position + 1
+	*p = *folded
+}
 
-			tmp[i][foldedDelta] += p.matrix[i][toFoldDelta]
-		}
-	}
+// foldOverY folds the lower part of the paper up over the upper one, along the row at position.
+func (p *Paper) foldOverY(position int) {
+	height := foldedSize(p.height, position)
 
-	p.matrix = tmp
+	p.fold(p.width, height, func(dot grid.Point) (result grid.Point, ok bool) {
+		result.X = dot.X
+		result.Y, ok = mirror(dot.Y, position, height)
+		return result, ok
+	})
 }
 
-func (p *Paper) interpretOrder(order string) {
-	parsedOrders := strings.Split(order, "=")
-	axis := parsedOrders[0]
-	value := extra.ConvertToInt(parsedOrders[1])
+// foldOverX folds the right part of the paper over the left one, along the column at position.
+func (p *Paper) foldOverX(position int) {
+	width := foldedSize(p.width, position)
 
-	if axis == "x" {
-		p.foldOverX(value)
+	p.fold(width, p.height, func(dot grid.Point) (result grid.Point, ok bool) {
+		result.X, ok = mirror(dot.X, position, width)
+		result.Y = dot.Y
+		return result, ok
+	})
+}
+
+// Order is a folding instruction, like `fold along y=7`.
+type Order struct {
+	axis     string
+	position int
+}
+
+func (o Order) String() string {
+	return fmt.Sprintf("fold along %s=%d", o.axis, o.position)
+}
+
+func (p *Paper) interpretOrder(order Order) {
+	if order.axis == "x" {
+		p.foldOverX(order.position)
 	} else {
-		p.foldOverY(value)
+		p.foldOverY(order.position)
 	}
 }
 
-//loadInput returns a matrix of integers representing the sheet of transparent paper, displaying 0s as `.` and 1s as `#`
-// this function will also return a slice of instructions about how to fold the paper.
-func loadInput(scanner *bufio.Scanner) (paper *Paper, instructions []string) {
-	instructions = make([]string, 0)
+// parseOrder reads a folding instruction from a line of the input.
+func parseOrder(line extra.Line) (order Order, err error) {
+	prefix, instruction, err := line.Cut("fold along ")
+	if err != nil {
+		return order, err
+	}
+	if prefix.Text != "" {
+		return order, prefix.Errorf(0, "unexpected %q before the folding instruction", prefix.Text)
+	}
 
-	coordinatesX := make([]int, 0)
-	coordinatesY := make([]int, 0)
+	axis, position, err := instruction.Cut("=")
+	if err != nil {
+		return order, err
+	}
+	if axis.Text != "x" && axis.Text != "y" {
+		return order, axis.Errorf(0, "unknown axis %q", axis.Text)
+	}
+	if order.position, err = position.Int(); err != nil {
+		return order, err
+	}
+	order.axis = axis.Text
+
+	return order, nil
+}
+
+// loadInput returns the sheet of transparent paper with its dots, this function will also return a slice of
+// instructions about how to fold the paper.
+func loadInput(scanner *extra.Scanner) (paper *Paper, instructions []Order, err error) {
+	instructions = make([]Order, 0)
+	paper = newPaper(0, 0)
 
 	for scanner.Scan() {
-		text := strings.Split(scanner.Text(), ",")
+		line := scanner.Line()
 
-		if len(text) == 2 {
-			// This is a matrix point.
-			x, y := extra.ConvertToInt(text[0]), extra.ConvertToInt(text[1])
+		if strings.Contains(line.Text, ",") {
+			// This is a dot on the paper.
+			x, y, err := line.Pair()
+			if err != nil {
+				return nil, nil, err
+			}
+			if x < 0 || y < 0 {
+				return nil, nil, line.Errorf(0, "coordinates cannot be negative")
+			}
 
-			coordinatesX = append(coordinatesX, x)
-			coordinatesY = append(coordinatesY, y)
+			paper.addDot(grid.Point{X: x, Y: y})
 
-		} else if text[0] != "" {
+		} else if line.Text != "" {
 			// This is a folding instruction.
-			instructions = append(instructions, strings.Split(text[0], " ")[2])
+			order, err := parseOrder(line)
+			if err != nil {
+				return nil, nil, err
+			}
+			instructions = append(instructions, order)
 		}
 
 	}
 
-	limitX := findLargest(coordinatesX) + 1
-	limitY := findLargest(coordinatesY) + 1
-	paperMatrix := make([][]int, limitY)
-	for i := range paperMatrix {
-		paperMatrix[i] = make([]int, limitX)
-	}
+	return paper, instructions, nil
+}
+
+// Puzzle holds the transparent paper and the instructions about how to fold it.
+type Puzzle struct {
+	paper  *Paper
+	orders []Order
+}
 
-	for i, x := range coordinatesX {
-		y := coordinatesY[i]
-		paperMatrix[y][x] = 1
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+	if p.paper, p.orders, err = loadInput(scanner); err != nil {
+		return err
 	}
 
-	return &Paper{matrix: paperMatrix}, instructions
+	return scanner.Err()
 }
 
-func findLargest(numbers []int) (result int) {
-	for _, elem := range numbers {
-		if elem > result {
-			result = elem
-		}
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	if len(p.orders) == 0 {
+		return solver.Answer{}, errors.New("there are no folding instructions")
 	}
 
-	return result
+	// Folding never modifies the set of dots in place, so a shallow copy of the paper is enough to keep the original intact.
+	paper := *p.paper
+	paper.interpretOrder(p.orders[0])
+
+	return solver.Number(paper.countDots()), nil
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day13_exercise01.txt")
-	if err != nil {
-		return -1, err
+// PartTwo reads the letters drawn by the dots once the paper is completely folded.
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	paper := *p.paper
+
+	for _, order := range p.orders {
+		paper.interpretOrder(order)
 	}
-	defer extra.CloseFile(file)
 
-	paper, orders := loadInput(bufio.NewScanner(file))
+	paper.printPaper(solver.Verbose)
 
-	paper.interpretOrder(orders[0])
+	text, err := ocr.Read(paper.dense())
+	if err != nil {
+		return solver.Answer{}, err
+	}
 
-	return paper.countDots(), nil
+	return solver.Text(text), nil
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day13_exercise01.txt")
-	if err != nil {
-		return -1, err
+// Draw returns the dots of the paper once folded as the instructions of a part say, where 1 is a dot.
+func (p *Puzzle) Draw(part int) (*grid.Grid[int], picture.Palette, error) {
+	if part != 1 && part != 2 {
+		return nil, nil, fmt.Errorf("invalid part %d, it must be 1 or 2", part)
 	}
-	defer extra.CloseFile(file)
-	paper, orders := loadInput(bufio.NewScanner(file))
 
+	paper, orders := *p.paper, p.orders
+	if part == 1 && len(orders) > 0 {
+		orders = orders[:1]
+	}
 	for _, order := range orders {
 		paper.interpretOrder(order)
 	}
 
-	paper.printPaper()
+	return picture.Bools(paper.dense()), picture.Mono, nil
+}
 
-	return paper.countDots(), nil
+// Orders returns the folding instructions.
+func (p *Puzzle) Orders() []Order {
+	return append([]Order(nil), p.orders...)
 }
 
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
+// Fold folds a copy of the paper following every instruction, calling visit after each of them with the number of
+// the step, starting at 1, and the dots of the paper at that point.  It stops at the first error returned by visit.
+func (p *Puzzle) Fold(visit func(step int, order Order, dots *grid.Grid[bool]) error) error {
+	paper := *p.paper
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
+	for i, order := range p.orders {
+		paper.interpretOrder(order)
+		if err := visit(i+1, order, paper.dense()); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
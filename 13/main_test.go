@@ -0,0 +1,424 @@
+package day13
+
+import (
+	"advent_2021/extra"
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+// letters folds into HI, with the dots of the I drawn mirrored on the right.
+const letters = `0,0
+3,0
+0,1
+3,1
+0,2
+1,2
+2,2
+3,2
+0,3
+3,3
+0,4
+3,4
+0,5
+3,5
+12,0
+11,0
+10,0
+11,1
+11,2
+11,3
+11,4
+12,5
+11,5
+10,5
+18,0
+
+fold along x=9
+`
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		// The example folds into a square, which is not a letter.
+		{Name: "example", Input: example, PartOne: "17"},
+		{Name: "letters", Input: letters, PartOne: "24", PartTwo: "HI"},
+	})
+
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+	if answer, err := p.PartTwo(); err == nil {
+		t.Errorf("PartTwo() read %v from a square", answer)
+	}
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 13, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+func TestFold(t *testing.T) {
+	paper, orders, err := loadInput(extra.NewScanner(strings.NewReader(example)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paper.foldOverY(orders[0].position)
+	if paper.width != 11 || paper.height != 7 {
+		t.Errorf("after foldOverY the paper is %dx%d, want 11x7", paper.width, paper.height)
+	}
+	if dots := paper.countDots(); dots != 17 {
+		t.Errorf("after foldOverY there are %d dots, want 17", dots)
+	}
+
+	paper.foldOverX(orders[1].position)
+	dots := paper.dense()
+	want := []string{
+		"#####",
+		"#...#",
+		"#...#",
+		"#...#",
+		"#####",
+		".....",
+		".....",
+	}
+	for y, row := range want {
+		for x, character := range row {
+			if dot := dots.Get(grid.Point{X: x, Y: y}); dot != (character == '#') {
+				t.Errorf("after foldOverX the dot at %d,%d is %v, want %q", x, y, dot, character)
+			}
+		}
+	}
+}
+
+func TestSparsePaper(t *testing.T) {
+	// A dense paper would need billions of cells.
+	input := "0,0\n1999999998,3\n7,1000000000\n\nfold along x=999999999\nfold along y=500000000\n"
+	paper, orders, err := loadInput(extra.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, order := range orders {
+		paper.interpretOrder(order)
+	}
+	if paper.width != 999999999 || paper.height != 500000000 {
+		t.Errorf("folded paper is %dx%d, want 999999999x500000000", paper.width, paper.height)
+	}
+
+	want := map[grid.Point]struct{}{{X: 0, Y: 0}: {}, {X: 0, Y: 3}: {}, {X: 7, Y: 0}: {}}
+	if !reflect.DeepEqual(paper.dots, want) {
+		t.Errorf("folded dots = %v, want %v", paper.dots, want)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+
+	for part, want := range map[int]int{1: 17, 2: 16} {
+		dots, _, err := p.Draw(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if min, max := picture.Range(dots); min != 0 || max != 1 {
+			t.Errorf("Draw(%d) values go from %d to %d, want 0 to 1", part, min, max)
+		}
+		if got := grid.Fold(dots, 0, func(sum int, _ grid.Point, dot int) int { return sum + dot }); got != want {
+			t.Errorf("Draw(%d) has %d dots, want %d", part, got, want)
+		}
+	}
+}
+
+func TestFoldSteps(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+
+	var steps []string
+	err := p.Fold(func(step int, order Order, dots *grid.Grid[bool]) error {
+		count := grid.Fold(dots, 0, func(count int, _ grid.Point, dot bool) int {
+			if dot {
+				count++
+			}
+			return count
+		})
+		steps = append(steps, fmt.Sprintf("%d: %v, %dx%d with %d dots", step, order, dots.Width(), dots.Height(), count))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"1: fold along y=7, 11x7 with 17 dots", "2: fold along x=5, 5x7 with 16 dots"}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("Fold() steps = %v, want %v", steps, want)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	if err := p.Fold(func(int, Order, *grid.Grid[bool]) error { calls++; return stop }); err != stop || calls != 1 {
+		t.Errorf("Fold() = %v after %d steps, want it to stop at the first one", err, calls)
+	}
+}
+
+// foldsInto reports whether a paper, once written as a puzzle input with some instructions and read back, folds into
+// the dots of a pattern.
+func foldsInto(t *testing.T, paper *Paper, orders []Order, pattern *Paper) bool {
+	t.Helper()
+
+	var input strings.Builder
+	if err := paper.WriteInput(&input, orders); err != nil {
+		t.Fatal(err)
+	}
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(input.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	folded := *p.paper
+	for _, order := range p.orders {
+		folded.interpretOrder(order)
+	}
+
+	return reflect.DeepEqual(folded.dots, pattern.dots)
+}
+
+func TestUnfold(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		folds   string
+		want    []string
+	}{
+		{"single fold", "#", "fold along x=1", []string{"0,0", "0,0 2,0", "2,0"}},
+		// The pattern is wider than the kept side, so only the folded side reaches its first column.
+		{"uneven fold", "#..", "fold along x=1", []string{"4,0"}},
+		// Only the last column reaches the first one of the pattern, so without a dot there the pattern would shift.
+		{"uneven fold without the first column", "....#", "fold along x=2", nil},
+		{"two folds without the first column", ".#\n..", "fold along y=1\nfold along x=1", nil},
+		// Before the second fold the paper is 1, 2 or 3 columns wide, so it is 3 or less, 4 or 5 columns wide at first.
+		{"uneven fold and even fold", "#", "fold along x=1\nfold along x=1", []string{"0,0", "0,0 2,0", "0,0 2,0 4,0",
+			"0,0 4,0", "2,0", "2,0 4,0", "3,0", "4,0"}},
+		{"blank", "..", "fold along x=2", []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := ReadPattern(strings.NewReader(tt.pattern))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, orders, err := loadInput(extra.NewScanner(strings.NewReader(tt.folds)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			unfolding := Unfold(pattern, orders)
+			var got []string
+			unfolding.PreImages(func(paper *Paper) bool {
+				dots := make([]grid.Point, 0)
+				for dot := range paper.dots {
+					dots = append(dots, dot)
+				}
+				sortPoints(dots)
+
+				names := make([]string, len(dots))
+				for i, dot := range dots {
+					names[i] = fmt.Sprintf("%d,%d", dot.X, dot.Y)
+				}
+				got = append(got, strings.Join(names, " "))
+
+				if !foldsInto(t, paper, orders, pattern) {
+					t.Errorf("%v does not fold into the pattern", names)
+				}
+				return true
+			})
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) || unfolding.Count().Int64() != int64(len(tt.want)) {
+				t.Errorf("PreImages() = %q, counted %v, want %q", got, unfolding.Count(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnfoldExample(t *testing.T) {
+	p := &Puzzle{}
+	if err := p.Parse(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+	pattern := *p.paper
+	for _, order := range p.orders {
+		pattern.interpretOrder(order)
+	}
+
+	unfolding := Unfold(&pattern, p.Orders())
+	if len(unfolding.Sheets) != 1 || unfolding.Sheets[0].Width != 11 || unfolding.Sheets[0].Height != 15 {
+		t.Fatalf("Unfold() sheets = %+v, want a single one of 11x15", unfolding.Sheets)
+	}
+
+	// Each of the 16 dots has 4 sources, so 15 ways to be drawn.
+	if want := new(big.Int).Exp(big.NewInt(15), big.NewInt(16), nil); unfolding.Count().Cmp(want) != 0 {
+		t.Errorf("Count() = %v, want %v", unfolding.Count(), want)
+	}
+
+	// The example itself is one of the papers, all of its dots are sources.
+	sources := make(map[grid.Point]bool)
+	for _, dots := range unfolding.Sheets[0].Sources {
+		for _, dot := range dots {
+			sources[dot] = true
+		}
+	}
+	for dot := range p.paper.dots {
+		if !sources[dot] {
+			t.Errorf("dot %v of the example is not a source", dot)
+		}
+	}
+
+	seen := 0
+	unfolding.PreImages(func(paper *Paper) bool {
+		if !foldsInto(t, paper, p.Orders(), &pattern) {
+			t.Errorf("paper %v does not fold into the pattern", paper.dots)
+		}
+
+		seen++
+		return seen < 100
+	})
+}
+
+// cleanFold folds a paper following some instructions, and reports whether none of its dots was on the line of a
+// fold, which makes it one of the papers Unfold finds.
+func cleanFold(paper *Paper, orders []Order) bool {
+	for _, order := range orders {
+		for dot := range paper.dots {
+			if (order.axis == "x" && dot.X == order.position) || (order.axis == "y" && dot.Y == order.position) {
+				return false
+			}
+		}
+		paper.interpretOrder(order)
+	}
+
+	return true
+}
+
+func TestUnfoldRoundTrip(t *testing.T) {
+	inputs := []string{
+		// The paper is 7 rows tall, less than it could be to fold into this pattern.
+		"5,6\n\nfold along y=5\nfold along y=3\n",
+	}
+
+	random := rand.New(rand.NewSource(13))
+	for len(inputs) < 500 {
+		var input strings.Builder
+		width, height := 1+random.Intn(9), 1+random.Intn(9)
+		for dots := 1 + random.Intn(3); dots > 0; dots-- {
+			fmt.Fprintf(&input, "%d,%d\n", random.Intn(width), random.Intn(height))
+		}
+		fmt.Fprintln(&input)
+		for folds := 1 + random.Intn(3); folds > 0; folds-- {
+			if random.Intn(2) == 0 {
+				fmt.Fprintf(&input, "fold along x=%d\n", random.Intn(width+1))
+			} else {
+				fmt.Fprintf(&input, "fold along y=%d\n", random.Intn(height+1))
+			}
+		}
+		inputs = append(inputs, input.String())
+	}
+
+	for _, input := range inputs {
+		p := &Puzzle{}
+		if err := p.Parse(strings.NewReader(input)); err != nil {
+			t.Fatal(err)
+		}
+		pattern := *p.paper
+		if !cleanFold(&pattern, p.orders) {
+			continue
+		}
+
+		// When there are few papers, all of them are checked and counted too.
+		unfolding := Unfold(&pattern, p.orders)
+		all := unfolding.Count().Cmp(big.NewInt(1000)) <= 0
+		found, seen := false, 0
+		unfolding.PreImages(func(paper *Paper) bool {
+			found = found || reflect.DeepEqual(paper.dots, p.paper.dots)
+			if seen++; all && !foldsInto(t, paper, p.orders, &pattern) {
+				t.Errorf("paper %v does not fold like the input\n%s", paper.dots, input)
+			}
+			return all || !found
+		})
+		if !found {
+			t.Errorf("the input\n%sis not one of the papers folding into\n%s", input, render(&pattern))
+		}
+		if all && unfolding.Count().Int64() != int64(seen) {
+			t.Errorf("Count() = %v for the input\n%swhich has %d papers", unfolding.Count(), input, seen)
+		}
+	}
+}
+
+func TestReadPatternErrors(t *testing.T) {
+	want := "input:2:2: unexpected 'x', dots are drawn as `#` and the rest as `.`"
+	if _, err := ReadPattern(strings.NewReader("#.\n.x\n")); err == nil || err.Error() != want {
+		t.Errorf("ReadPattern() error = %v, want %s", err, want)
+	}
+}
+
+// render draws the dots of the paper as `#`, and the rest as `.`.
+func render(paper *Paper) string {
+	var result strings.Builder
+	paper.dense().Print(&result, func(dot bool) string {
+		if dot {
+			return "#"
+		}
+		return "."
+	})
+
+	return result.String()
+}
+
+func TestUnevenFolds(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		// Rows 3 to 9 are folded over rows 0 and 1, which are moved down to fit them.
+		{"larger lower part", "0,0\n0,3\n0,9\n\nfold along y=2\n", "#\n.\n.\n.\n.\n#\n#\n"},
+		{"larger right part", "0,0\n3,1\n9,0\n\nfold along x=2\n", "#....#.\n......#\n"},
+		{"past the end", "0,0\n1,1\n\nfold along y=4\n", "#.\n.#\n..\n..\n"},
+		{"repeated", "0,0\n10,2\n0,4\n\nfold along x=3\nfold along x=5\nfold along y=1\nfold along y=1\n", "#...#\n"},
+		{"example", example, "#####\n#...#\n#...#\n#...#\n#####\n.....\n.....\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paper, orders, err := loadInput(extra.NewScanner(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, order := range orders {
+				paper.interpretOrder(order)
+			}
+			if got := render(paper); got != tt.want {
+				t.Errorf("folded paper =\n%swant\n%s", got, tt.want)
+			}
+		})
+	}
+}
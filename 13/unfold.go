@@ -0,0 +1,324 @@
+package day13
+
+import (
+	"advent_2021/extra"
+	"advent_2021/grid"
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// ReadPattern reads the drawing of a folded paper, one row per line, with dots drawn as `#` and the rest as `.`.
+func ReadPattern(input io.Reader) (*Paper, error) {
+	scanner := extra.NewScanner(input)
+	paper := newPaper(0, 0)
+
+	for y := 0; scanner.Scan(); y++ {
+		line := scanner.Line()
+		for x, cell := range line.Text {
+			switch cell {
+			case '#':
+				paper.addDot(grid.Point{X: x, Y: y})
+			case '.':
+			default:
+				return nil, line.Errorf(x, "unexpected %q, dots are drawn as `#` and the rest as `.`", cell)
+			}
+		}
+
+		if len(line.Text) > paper.width {
+			paper.width = len(line.Text)
+		}
+		paper.height = y + 1
+	}
+
+	return paper, scanner.Err()
+}
+
+// sortPoints sorts points by row, and then by column.
+func sortPoints(points []grid.Point) {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Y != points[j].Y {
+			return points[i].Y < points[j].Y
+		}
+		return points[i].X < points[j].X
+	})
+}
+
+// Unfolding describes every paper which folds into a pattern, without dots on the lines of the folds.  The size of a
+// paper decides where its dots end up, and different papers fold into the same pattern from different sizes, so there
+// is a Sheet for each of them.
+type Unfolding struct {
+	// Dots are the dots of the pattern, sorted by row and column.
+	Dots   []grid.Point
+	Sheets []Sheet
+}
+
+// Sheet describes the papers of a given size which fold into a pattern.  Each dot of the pattern comes from any
+// non-empty set of its sources, and the paper has no dots anywhere else.
+//
+// A paper read from a puzzle input is only as large as its dots, so when it has to be exactly as large as the sheet
+// for the folds to work out, it must also have a dot on its last column or row.
+type Sheet struct {
+	Width, Height int
+	// Sources are the points of the paper which end on each dot of the pattern.
+	Sources [][]grid.Point
+	// LastColumn and LastRow tell whether the paper needs a dot on its last column or row.
+	LastColumn, LastRow bool
+}
+
+// unfoldedAxis is a way to undo the folds along an axis: the size of the paper along it, whether the paper must reach
+// its end, and the coordinates which end on each coordinate of the pattern.
+type unfoldedAxis struct {
+	size    int
+	last    bool
+	sources [][]int
+}
+
+// unmirror returns the coordinates which end on a coordinate after folding at a position a paper of the given sizes,
+// the inverse of mirror.
+func unmirror(coordinate, position, size, folded int) (result []int) {
+	kept := coordinate - folded + position
+	if kept >= 0 && kept < position && kept < size {
+		result = append(result, kept)
+	}
+	if mirrored := 2*position - kept; mirrored > position && mirrored < size {
+		result = append(result, mirrored)
+	}
+
+	return result
+}
+
+// unfoldAxis returns every way to undo some folds along an axis, at the given positions, given the size of the pattern
+// along it.  A pattern smaller than the last fold is taken to reach up to it.
+//
+// A fold leaves the paper as large as its larger side.  When that is the side past the fold, the size before it is
+// known, but when it is the side before the fold, the paper could have had any size up to twice it.  Before the first
+// fold, all of those sizes move the dots in the same way, so the largest one covers them all, and the paper does not
+// need to reach its end.
+func unfoldAxis(folded int, positions []int) []unfoldedAxis {
+	if n := len(positions); n > 0 && folded < positions[n-1] {
+		folded = positions[n-1]
+	}
+
+	sources := make([][]int, folded)
+	for coordinate := range sources {
+		sources[coordinate] = []int{coordinate}
+	}
+
+	var result []unfoldedAxis
+	var undo func(folds, folded int, sources [][]int)
+	undo = func(folds, folded int, sources [][]int) {
+		if folds == 0 {
+			result = append(result, unfoldedAxis{size: folded, sources: sources})
+			return
+		}
+
+		// The sizes the paper could have before the fold, which are at least the position of the previous one.
+		position, smallest := positions[folds-1], 0
+		if folds > 1 {
+			smallest = positions[folds-2]
+		}
+		sizes := []int{folded + position + 1}
+		if folded == position && folds > 1 {
+			sizes = sizes[:0]
+			for size := smallest; size <= 2*position+1; size++ {
+				sizes = append(sizes, size)
+			}
+		}
+
+		for _, size := range sizes {
+			if size < smallest {
+				continue
+			}
+
+			unfolded := make([][]int, len(sources))
+			for i, coordinates := range sources {
+				for _, coordinate := range coordinates {
+					unfolded[i] = append(unfolded[i], unmirror(coordinate, position, size, folded)...)
+				}
+			}
+
+			before := len(result)
+			undo(folds-1, size, unfolded)
+			if folds == 1 && folded > position {
+				result[before].last = true
+			}
+		}
+	}
+	undo(len(positions), folded, sources)
+
+	return result
+}
+
+// Unfold returns every paper which folds into a pattern following some instructions.  A pattern smaller than a fold
+// is taken to reach up to it.
+func Unfold(pattern *Paper, orders []Order) *Unfolding {
+	unfolding := &Unfolding{}
+	for dot := range pattern.dots {
+		unfolding.Dots = append(unfolding.Dots, dot)
+	}
+	sortPoints(unfolding.Dots)
+
+	// Folds along one axis never move the dots along the other one, so each axis is undone on its own.
+	var columns, rows []int
+	for _, order := range orders {
+		if order.axis == "x" {
+			columns = append(columns, order.position)
+		} else {
+			rows = append(rows, order.position)
+		}
+	}
+
+	for _, x := range unfoldAxis(pattern.width, columns) {
+		for _, y := range unfoldAxis(pattern.height, rows) {
+			sheet, reachable := Sheet{Width: x.size, Height: y.size, LastColumn: x.last, LastRow: y.last}, true
+			for _, dot := range unfolding.Dots {
+				reachable = reachable && len(x.sources[dot.X]) > 0 && len(y.sources[dot.Y]) > 0
+				sources := make([]grid.Point, 0, len(x.sources[dot.X])*len(y.sources[dot.Y]))
+				for _, column := range x.sources[dot.X] {
+					for _, row := range y.sources[dot.Y] {
+						sources = append(sources, grid.Point{X: column, Y: row})
+					}
+				}
+				sortPoints(sources)
+				sheet.Sources = append(sheet.Sources, sources)
+			}
+
+			// A paper of this size cannot fold into the pattern when some of its dots come from nowhere.
+			if reachable {
+				unfolding.Sheets = append(unfolding.Sheets, sheet)
+			}
+		}
+	}
+
+	return unfolding
+}
+
+// countAvoiding returns the number of ways to draw the dots of the pattern without the points given by avoid.
+func (s *Sheet) countAvoiding(avoid func(point grid.Point) bool) *big.Int {
+	result := big.NewInt(1)
+	for _, sources := range s.Sources {
+		allowed := 0
+		for _, source := range sources {
+			if !avoid(source) {
+				allowed++
+			}
+		}
+
+		choices := new(big.Int).Lsh(big.NewInt(1), uint(allowed))
+		result.Mul(result, choices.Sub(choices, big.NewInt(1)))
+	}
+
+	return result
+}
+
+// Count returns the number of papers of the sheet which fold into the pattern.
+func (s *Sheet) Count() *big.Int {
+	none := func(grid.Point) bool { return false }
+	column := func(point grid.Point) bool { return point.X == s.Width-1 }
+	row := func(point grid.Point) bool { return point.Y == s.Height-1 }
+
+	// Every way to draw the dots, but those missing a last column or row which needs a dot.
+	result := s.countAvoiding(none)
+	if s.LastColumn {
+		result.Sub(result, s.countAvoiding(column))
+	}
+	if s.LastRow {
+		result.Sub(result, s.countAvoiding(row))
+	}
+	if s.LastColumn && s.LastRow {
+		result.Add(result, s.countAvoiding(func(point grid.Point) bool { return column(point) || row(point) }))
+	}
+
+	return result
+}
+
+// Count returns the number of papers which fold into the pattern.  Papers of different sheets have different sizes,
+// so none of them is counted twice.
+func (u *Unfolding) Count() *big.Int {
+	result := new(big.Int)
+	for i := range u.Sheets {
+		result.Add(result, u.Sheets[i].Count())
+	}
+
+	return result
+}
+
+// PreImages calls visit with every paper of the sheet which folds into the pattern, until it returns false, which it
+// returns as well.
+func (s *Sheet) PreImages(visit func(paper *Paper) bool) bool {
+	chosen := make(map[grid.Point]struct{})
+
+	// complete reports whether the chosen dots reach the last column and row when they need to.
+	complete := func() bool {
+		column, row := !s.LastColumn, !s.LastRow
+		for point := range chosen {
+			column = column || point.X == s.Width-1
+			row = row || point.Y == s.Height-1
+		}
+		return column && row
+	}
+
+	// choose decides whether each source of each dot has a dot, making sure each dot gets at least one of them.
+	var choose func(dot, source int, some bool) bool
+	choose = func(dot, source int, some bool) bool {
+		if dot == len(s.Sources) {
+			if !complete() {
+				return true
+			}
+
+			paper := newPaper(s.Width, s.Height)
+			for point := range chosen {
+				paper.dots[point] = struct{}{}
+			}
+			return visit(paper)
+		}
+
+		sources := s.Sources[dot]
+		if source == len(sources) {
+			return !some || choose(dot+1, 0, false)
+		}
+
+		chosen[sources[source]] = struct{}{}
+		if !choose(dot, source+1, true) {
+			return false
+		}
+		delete(chosen, sources[source])
+
+		return choose(dot, source+1, some)
+	}
+
+	return choose(0, 0, false)
+}
+
+// PreImages calls visit with every paper which folds into the pattern, sheet by sheet, until it returns false.
+func (u *Unfolding) PreImages(visit func(paper *Paper) bool) {
+	for i := range u.Sheets {
+		if !u.Sheets[i].PreImages(visit) {
+			return
+		}
+	}
+}
+
+// WriteInput writes the paper followed by some folding instructions, in the format of the puzzle input.  The dots are
+// sorted by row and column.
+func (p *Paper) WriteInput(w io.Writer, orders []Order) error {
+	dots := make([]grid.Point, 0, len(p.dots))
+	for dot := range p.dots {
+		dots = append(dots, dot)
+	}
+	sortPoints(dots)
+
+	output := bufio.NewWriter(w)
+	for _, dot := range dots {
+		fmt.Fprintf(output, "%d,%d\n", dot.X, dot.Y)
+	}
+	fmt.Fprintln(output)
+	for _, order := range orders {
+		fmt.Fprintln(output, order)
+	}
+
+	return output.Flush()
+}
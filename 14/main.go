@@ -1,28 +1,59 @@
-package main
+package day14
 
 import (
 	"advent_2021/extra"
-	"bufio"
-	"fmt"
-	"log"
+	"advent_2021/solver"
+	"io"
 	"math"
-	"os"
-	"strings"
 )
 
-func loadInput(scanner *bufio.Scanner) (template []rune, rules map[string]string) {
-	scanner.Scan()
+func init() {
+	solver.Register(14, func() solver.Solver { return &Puzzle{} })
+}
+
+// loadInput reads the polymer template from the first line, and the pair insertion rules after the blank line.
+func loadInput(scanner *extra.Scanner) (template []rune, rules map[string]string, err error) {
+	if !scanner.Scan() || scanner.Text() == "" {
+		return nil, nil, scanner.Errorf("missing the polymer template")
+	}
 	template = []rune(scanner.Text())
-	scanner.Scan()
+	if scanner.Scan() && scanner.Text() != "" {
+		return nil, nil, scanner.Line().Errorf(0, "expected a blank line after the polymer template")
+	}
 
 	rules = make(map[string]string)
 
 	for scanner.Scan() {
-		parsed := strings.Split(scanner.Text(), " -> ")
-		rules[parsed[0]] = parsed[1]
+		pair, insertion, err := scanner.Line().Rule()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(pair.Text) != 2 {
+			return nil, nil, pair.Errorf(0, "expected a pair of elements, found %q", pair.Text)
+		}
+		if len(insertion.Text) != 1 {
+			return nil, nil, insertion.Errorf(0, "expected a single element, found %q", insertion.Text)
+		}
+
+		rules[pair.Text] = insertion.Text
+	}
+
+	return template, rules, nil
+}
+
+// Puzzle holds the polymer template and the pair insertion rules.
+type Puzzle struct {
+	template []rune
+	rules    map[string]string
+}
+
+func (p *Puzzle) Parse(input io.Reader) (err error) {
+	scanner := extra.NewScanner(input)
+	if p.template, p.rules, err = loadInput(scanner); err != nil {
+		return err
 	}
 
-	return template, rules
+	return scanner.Err()
 }
 
 func countElements(pairs map[string]int, template []rune) (result map[string]int) {
@@ -68,12 +99,17 @@ func countPairs(template []rune) (result map[string]int) {
 	return result
 }
 
-// evaluatePairs will, considering the rules provided, how many pairs appear in the resulting new template.
+// evaluatePairs will, considering the rules provided, how many pairs appear in the resulting new template.  Pairs
+// without a rule are left as they are.
 func evaluatePairs(pairs map[string]int, rules map[string]string) (result map[string]int) {
 	result = make(map[string]int)
 
 	for pair, count := range pairs {
-		insertion := rules[pair] // This is common to know the following pairs
+		insertion, ok := rules[pair] // This is common to know the following pairs
+		if !ok {
+			result[pair] += count
+			continue
+		}
 
 		// We know that AB -> A<insertion> and <insertion>B.
 		result[string(pair[0])+insertion] += count
@@ -83,60 +119,28 @@ func evaluatePairs(pairs map[string]int, rules map[string]string) (result map[st
 	return result
 }
 
-func firstExercise() (int, error) {
-	file, err := os.Open("inputs/day14_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	template, rules := loadInput(bufio.NewScanner(file))
-
-	pairs := countPairs(template)
+func (p *Puzzle) PartOne() (solver.Answer, error) {
+	pairs := countPairs(p.template)
 
 	for i := 0; i < 10; i++ {
-		pairs = evaluatePairs(pairs, rules)
+		pairs = evaluatePairs(pairs, p.rules)
 	}
 
-	count := countElements(pairs, template)
-	fmt.Printf("Count of elements: %+v\n.", count)
+	count := countElements(pairs, p.template)
+	solver.Debugf("Count of elements: %+v\n", count)
 
-	return calculateResult(count), nil
+	return solver.Number(calculateResult(count)), nil
 }
 
-func secondExercise() (int, error) {
-	file, err := os.Open("inputs/day14_exercise01.txt")
-	if err != nil {
-		return -1, err
-	}
-	defer extra.CloseFile(file)
-
-	template, rules := loadInput(bufio.NewScanner(file))
-
-	pairs := countPairs(template)
+func (p *Puzzle) PartTwo() (solver.Answer, error) {
+	pairs := countPairs(p.template)
 
 	for i := 0; i < 40; i++ {
-		pairs = evaluatePairs(pairs, rules)
+		pairs = evaluatePairs(pairs, p.rules)
 	}
 
-	count := countElements(pairs, template)
-	fmt.Printf("Count of elements: %+v\n.", count)
-
-	return calculateResult(count), nil
-}
-
-func main() {
-	firstResult, err := firstExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the first exercise is: %d.\n", firstResult)
-	}
+	count := countElements(pairs, p.template)
+	solver.Debugf("Count of elements: %+v\n", count)
 
-	secondResult, err := secondExercise()
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Printf("The result of the second exercise is: %d.\n", secondResult)
-	}
+	return solver.Number(calculateResult(count)), nil
 }
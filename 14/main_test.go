@@ -0,0 +1,63 @@
+package day14
+
+import (
+	"advent_2021/extra"
+	"advent_2021/solver"
+	"advent_2021/solver/solvertest"
+	_ "embed"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/example.txt
+var example string
+
+func TestPuzzle(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		{Name: "example", Input: example, PartOne: "1588", PartTwo: "2188189693529"},
+	})
+}
+
+func BenchmarkPuzzle(b *testing.B) {
+	solvertest.Benchmark(b, 14, func() solver.Solver { return &Puzzle{} }, example)
+}
+
+func TestEvaluatePairs(t *testing.T) {
+	template, rules, err := loadInput(extra.NewScanner(strings.NewReader(example)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NNCB becomes NCNBCHB after one step.
+	want := map[string]int{"NC": 1, "CN": 1, "NB": 1, "BC": 1, "CH": 1, "HB": 1}
+	if got := evaluatePairs(countPairs(template), rules); !reflect.DeepEqual(got, want) {
+		t.Errorf("evaluatePairs() = %v, want %v", got, want)
+	}
+}
+
+func TestMissingRules(t *testing.T) {
+	solvertest.Run(t, func() solver.Solver { return &Puzzle{} }, []solvertest.Case{
+		// NNCB becomes NCNBCB and then NBCNBCB, which stays the same as none of its pairs has a rule.
+		{Name: "some rules", Input: "NNCB\n\nNN -> C\nNC -> B\n", PartOne: "1", PartTwo: "1"},
+		{Name: "no rules", Input: "NNCB\n", PartOne: "1", PartTwo: "1"},
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, input string
+	}{
+		{"no template", ""},
+		{"no blank line", "NNCB\nCH -> B\n"},
+		{"long insertion", "NNCB\n\nCH -> BB\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Puzzle{}).Parse(strings.NewReader(tt.input)); err == nil {
+				t.Error("Parse() accepted an invalid input")
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package answers keeps the accepted answer of each part of each day, for every input we know, so refactors can be
+// checked against them.
+//
+// The answers are stored in a plain text file, one answer per line with the day, part, input file and answer
+// separated by white space.  Lines starting with `#` are comments.  An answer of `-` records that a part has no
+// answer for that input, like an example which was not made for it, so checking it is skipped.
+package answers
+
+import (
+	"advent_2021/extra"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Status is the result of checking an answer against the store.
+type Status int
+
+const (
+	Unknown Status = iota
+	Pass
+	Fail
+	Skipped
+)
+
+// NoAnswer is the answer stored for the parts which have none for an input.
+const NoAnswer = "-"
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Fail:
+		return "fail"
+	case Skipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// key identifies an answer, the input is kept as a clean, slash separated, path.
+type key struct {
+	day, part int
+	input     string
+}
+
+// Store holds the known answers, and the file they were read from.
+type Store struct {
+	path    string
+	answers map[key]string
+}
+
+// Load reads the answers stored in a file.  A missing file is not an error, it just means no answer is known yet.
+func Load(path string) (*Store, error) {
+	store := &Store{path: path, answers: make(map[key]string)}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := extra.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Line()
+		if strings.TrimSpace(line.Text) == "" || strings.HasPrefix(line.Text, "#") {
+			continue
+		}
+
+		fields := line.Fields()
+		if len(fields) != 4 {
+			return nil, line.Errorf(0, "expected day, part, input and answer, found %d fields", len(fields))
+		}
+		day, err := fields[0].Int()
+		if err != nil {
+			return nil, err
+		}
+		part, err := fields[1].Int()
+		if err != nil {
+			return nil, err
+		}
+
+		store.answers[newKey(day, part, fields[2].Text)] = fields[3].Text
+	}
+
+	return store, scanner.Err()
+}
+
+func newKey(day, part int, input string) key {
+	return key{day: day, part: part, input: filepath.ToSlash(filepath.Clean(input))}
+}
+
+// Lookup returns the accepted answer for a part and input, or false if it is not known.
+func (s *Store) Lookup(day, part int, input string) (string, bool) {
+	answer, ok := s.answers[newKey(day, part, input)]
+	return answer, ok
+}
+
+// Check compares an answer with the accepted one.  Parts without an answer for the input are skipped, whatever the
+// answer given.
+func (s *Store) Check(day, part int, input string, answer string) Status {
+	expected, ok := s.Lookup(day, part, input)
+	switch {
+	case !ok:
+		return Unknown
+	case expected == NoAnswer:
+		return Skipped
+	case expected == answer:
+		return Pass
+	default:
+		return Fail
+	}
+}
+
+// Record sets the accepted answer for a part and input, it will only be persisted by Save.
+func (s *Store) Record(day, part int, input string, answer string) error {
+	if answer == "" || strings.ContainsAny(answer, " \t\n") {
+		return fmt.Errorf("answer %q cannot be stored, it must be a single word", answer)
+	}
+
+	s.answers[newKey(day, part, input)] = answer
+	return nil
+}
+
+// Save writes all the answers back to the file they were loaded from, sorted by day, part and input.
+func (s *Store) Save() error {
+	keys := make([]key, 0, len(s.answers))
+	for k := range s.answers {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].day != keys[j].day {
+			return keys[i].day < keys[j].day
+		}
+		if keys[i].part != keys[j].part {
+			return keys[i].part < keys[j].part
+		}
+		return keys[i].input < keys[j].input
+	})
+
+	var builder strings.Builder
+	builder.WriteString("# day\tpart\tinput\tanswer\n")
+	for _, k := range keys {
+		fmt.Fprintf(&builder, "%d\t%d\t%s\t%s\n", k.day, k.part, k.input, s.answers[k])
+	}
+
+	return os.WriteFile(s.path, []byte(builder.String()), 0644)
+}
@@ -0,0 +1,135 @@
+package answers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.txt")
+
+	// A missing file holds no answers yet.
+	store, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, answer := range []struct {
+		day, part int
+		input     string
+		answer    string
+	}{
+		{13, 2, "inputs/day13_exercise01.txt", "LGHEGUEJ"},
+		{9, 1, "09/testdata/example.txt", "15"},
+		{13, 2, "13/testdata/example.txt", NoAnswer},
+	} {
+		if err := store.Record(answer.day, answer.part, answer.input, answer.answer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# day\tpart\tinput\tanswer\n" +
+		"9\t1\t09/testdata/example.txt\t15\n" +
+		"13\t2\t13/testdata/example.txt\t-\n" +
+		"13\t2\tinputs/day13_exercise01.txt\tLGHEGUEJ\n"
+	if string(content) != want {
+		t.Errorf("saved answers =\n%swant\n%s", content, want)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Inputs are looked up by their clean path.
+	if answer, ok := loaded.Lookup(9, 1, "./09/../09/testdata/example.txt"); !ok || answer != "15" {
+		t.Errorf("Lookup() = %q, %v, want 15", answer, ok)
+	}
+	if _, ok := loaded.Lookup(9, 2, "09/testdata/example.txt"); ok {
+		t.Error("Lookup() found an answer which was never recorded")
+	}
+}
+
+func TestLoadCommentsAndBlankLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.txt")
+	content := "# day\tpart\tinput\tanswer\n\n   \n1 1 01/testdata/example.txt 7\n# 1 2 01/testdata/example.txt 5\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.answers) != 1 {
+		t.Errorf("Load() read %d answers, want 1", len(store.answers))
+	}
+	if answer, ok := store.Lookup(1, 1, "01/testdata/example.txt"); !ok || answer != "7" {
+		t.Errorf("Lookup() = %q, %v, want 7", answer, ok)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"too few fields", "1 1 7\n", ":1:1: expected day, part, input and answer, found 3 fields"},
+		{"too many fields", "# comment\n1 1 input.txt 7 8\n", ":2:1: expected day, part, input and answer, found 5 fields"},
+		{"bad day", "one 1 input.txt 7\n", ":1:1:"},
+		{"bad part", "1 two input.txt 7\n", ":1:3:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "answers.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := Load(path); err == nil || !strings.HasPrefix(err.Error(), path+tt.want) {
+				t.Errorf("Load() error = %v, want it to start with %s%s", err, path, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	store := &Store{answers: make(map[key]string)}
+	for _, answer := range []string{"", "two words", "tab\tseparated", "two\nlines"} {
+		if err := store.Record(1, 1, "input.txt", answer); err == nil {
+			t.Errorf("Record() accepted the answer %q", answer)
+		}
+	}
+	if err := store.Record(1, 1, "input.txt", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Record(1, 2, "input.txt", NoAnswer); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		part   int
+		answer string
+		want   Status
+	}{
+		{1, "7", Pass},
+		{1, "8", Fail},
+		{2, "5", Skipped},
+		{2, "", Skipped},
+	}
+	for _, tt := range tests {
+		if got := store.Check(1, tt.part, "input.txt", tt.answer); got != tt.want {
+			t.Errorf("Check(part %d, %q) = %v, want %v", tt.part, tt.answer, got, tt.want)
+		}
+	}
+	if got := store.Check(2, 1, "input.txt", "7"); got != Unknown {
+		t.Errorf("Check() of an unknown part = %v, want unknown", got)
+	}
+}
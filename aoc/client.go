@@ -0,0 +1,161 @@
+// Package aoc talks to the Advent of Code website, or any server behaving like it, to download puzzle inputs.
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Year is the edition of Advent of Code this repository solves.
+	Year = 2021
+	// DefaultBaseURL is the address of the Advent of Code website.
+	DefaultBaseURL = "https://adventofcode.com"
+	// DefaultInterval is the minimum time between two requests, to be polite with the servers.
+	DefaultInterval = 3 * time.Second
+	// SessionEnv is the environment variable holding the session token.
+	SessionEnv = "AOC_SESSION"
+	// BaseURLEnv is the environment variable overriding the base URL.
+	BaseURLEnv = "AOC_BASE_URL"
+)
+
+// Client makes authenticated requests, never faster than once per Interval.
+type Client struct {
+	BaseURL    string
+	Session    string
+	Interval   time.Duration
+	HTTPClient *http.Client
+
+	mutex       sync.Mutex
+	lastRequest time.Time
+}
+
+// NewClient returns a Client with the default interval between requests.  An empty base URL uses the one from the
+// environment or, failing that, the Advent of Code website.
+func NewClient(baseURL, session string) *Client {
+	if baseURL == "" {
+		baseURL = os.Getenv(BaseURLEnv)
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Session:    session,
+		Interval:   DefaultInterval,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// LoadSession returns the session token, read from the environment or, when it is not set there, from the given file.
+// An empty path reads the `session` file in the advent_2021 directory of the user configuration.
+func LoadSession(path string) (string, error) {
+	if session := strings.TrimSpace(os.Getenv(SessionEnv)); session != "" {
+		return session, nil
+	}
+
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(configDir, "advent_2021", "session")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("no session token in $%s, and it cannot be read from a file: %w", SessionEnv, err)
+	}
+	if session := strings.TrimSpace(string(content)); session != "" {
+		return session, nil
+	}
+
+	return "", fmt.Errorf("session file %s is empty", path)
+}
+
+// throttle waits until at least Interval has passed since the previous request.
+func (c *Client) throttle() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if wait := c.Interval - time.Since(c.lastRequest); !c.lastRequest.IsZero() && wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastRequest = time.Now()
+}
+
+// do sends an authenticated request and returns the body of the response, failing on any status but 200 OK.
+func (c *Client) do(request *http.Request) ([]byte, error) {
+	if c.Session == "" {
+		return nil, errors.New("missing session token")
+	}
+	request.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	request.Header.Set("User-Agent", "github.com/jotanavarro/advent_2021")
+
+	c.throttle()
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s: %s", request.Method, request.URL, response.Status,
+			strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// dayURL returns the address of a page of the puzzle of a given day.
+func (c *Client) dayURL(day int, page string) string {
+	return fmt.Sprintf("%s/%d/day/%d%s", c.BaseURL, Year, day, page)
+}
+
+// Input downloads the puzzle input of a given day.
+func (c *Client) Input(day int) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, c.dayURL(day, "/input"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(request)
+}
+
+// CacheInput makes sure the input of a given day is stored in path, downloading it only when the file does not exist
+// yet, or is empty, like the placeholders left for inputs not downloaded.  It returns whether the input was downloaded.
+func (c *Client) CacheInput(day int, path string) (bool, error) {
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return false, nil
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	input, err := c.Input(day)
+	if err != nil {
+		return false, err
+	}
+
+	// We write to a temporary file first, so an interrupted download never leaves a partial input in the cache.
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, input, 0644); err != nil {
+		return false, err
+	}
+
+	return true, os.Rename(temporary, path)
+}
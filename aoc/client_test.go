@@ -0,0 +1,140 @@
+package aoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newServer starts a stand-in for the puzzle server, serving the input of day 1 to the `secret` session.
+func newServer(t *testing.T, requests *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/2021/day/1/input" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write([]byte("199\n200\n208\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCacheInput(t *testing.T) {
+	var requests int32
+	server := newServer(t, &requests)
+	client := NewClient(server.URL, "secret")
+	client.Interval = 0
+	path := filepath.Join(t.TempDir(), "inputs", "day01_exercise01.txt")
+
+	downloaded, err := client.CacheInput(1, path)
+	if err != nil || !downloaded {
+		t.Fatalf("first CacheInput() = %v, %v, want a download", downloaded, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "199\n200\n208\n" {
+		t.Errorf("cached input = %q", content)
+	}
+
+	downloaded, err = client.CacheInput(1, path)
+	if err != nil || downloaded {
+		t.Fatalf("second CacheInput() = %v, %v, want the cached file", downloaded, err)
+	}
+	if requests != 1 {
+		t.Errorf("the server got %d requests, want 1", requests)
+	}
+}
+
+func TestCacheEmptyInput(t *testing.T) {
+	var requests int32
+	server := newServer(t, &requests)
+	client := NewClient(server.URL, "secret")
+	client.Interval = 0
+
+	// An empty file is a placeholder, not an input.
+	path := filepath.Join(t.TempDir(), "day01_exercise01.txt")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	downloaded, err := client.CacheInput(1, path)
+	if err != nil || !downloaded {
+		t.Fatalf("CacheInput() = %v, %v, want a download", downloaded, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "199\n200\n208\n" {
+		t.Errorf("cached input = %q", content)
+	}
+}
+
+func TestInputErrors(t *testing.T) {
+	var requests int32
+	server := newServer(t, &requests)
+
+	tests := []struct {
+		name, session string
+		day           int
+		want          string
+	}{
+		{"wrong session", "other", 1, "400 Bad Request: Puzzle inputs differ by user."},
+		{"missing day", "secret", 2, "404 Not Found"},
+		{"no session", "", 1, "missing session token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(server.URL, tt.session)
+			client.Interval = 0
+
+			if _, err := client.Input(tt.day); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Input() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	var requests int32
+	server := newServer(t, &requests)
+	client := NewClient(server.URL, "secret")
+	client.Interval = 50 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Input(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 2*client.Interval {
+		t.Errorf("three requests took %v, want at least %v", elapsed, 2*client.Interval)
+	}
+}
+
+func TestLoadSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(SessionEnv, "")
+	if session, err := LoadSession(path); err != nil || session != "from-file" {
+		t.Errorf("LoadSession() = %q, %v, want the file contents", session, err)
+	}
+
+	t.Setenv(SessionEnv, "from-env")
+	if session, err := LoadSession(path); err != nil || session != "from-env" {
+		t.Errorf("LoadSession() = %q, %v, want the environment", session, err)
+	}
+}
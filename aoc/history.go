@@ -0,0 +1,148 @@
+package aoc
+
+import (
+	"advent_2021/extra"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Submission is an answer sent to the server, with its verdict.
+type Submission struct {
+	Day, Part int
+	Answer    string
+	Verdict   Verdict
+	Time      time.Time
+	// NotBefore is the earliest time the server accepts another answer.
+	NotBefore time.Time
+}
+
+// History keeps every answer submitted, so we never send the same guess twice and can bound the following ones.
+//
+// It is stored in a plain text file, one submission per line with the day, part, answer, verdict, submission time and
+// the time before which no other answer is accepted, separated by tabs.
+type History struct {
+	path        string
+	submissions []Submission
+}
+
+// LoadHistory reads the submissions stored in a file.  A missing file is an empty history.
+func LoadHistory(path string) (*History, error) {
+	history := &History{path: path}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := extra.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Line()
+		if strings.TrimSpace(line.Text) == "" || strings.HasPrefix(line.Text, "#") {
+			continue
+		}
+
+		submission, err := parseSubmission(line)
+		if err != nil {
+			return nil, err
+		}
+		history.submissions = append(history.submissions, submission)
+	}
+
+	return history, scanner.Err()
+}
+
+func parseSubmission(line extra.Line) (submission Submission, err error) {
+	fields := line.Split("\t")
+	if len(fields) != 6 {
+		return submission, line.Errorf(0, "expected 6 tab separated fields, found %d", len(fields))
+	}
+
+	if submission.Day, err = fields[0].Int(); err != nil {
+		return submission, err
+	}
+	if submission.Part, err = fields[1].Int(); err != nil {
+		return submission, err
+	}
+	submission.Answer = fields[2].Text
+	if submission.Verdict, err = ParseVerdict(fields[3].Text); err != nil {
+		return submission, fields[3].Errorf(0, "%v", err)
+	}
+	if submission.Time, err = time.Parse(time.RFC3339, fields[4].Text); err != nil {
+		return submission, fields[4].Errorf(0, "%v", err)
+	}
+	if submission.NotBefore, err = time.Parse(time.RFC3339, fields[5].Text); err != nil {
+		return submission, fields[5].Errorf(0, "%v", err)
+	}
+
+	return submission, nil
+}
+
+// Add appends a submission to the history, it will only be persisted by Save.
+func (h *History) Add(submission Submission) {
+	h.submissions = append(h.submissions, submission)
+}
+
+// Save writes the history back to the file it was loaded from.
+func (h *History) Save() error {
+	var builder strings.Builder
+	builder.WriteString("# day\tpart\tanswer\tverdict\ttime\tnot before\n")
+
+	for _, s := range h.submissions {
+		fmt.Fprintf(&builder, "%d\t%d\t%s\t%s\t%s\t%s\n", s.Day, s.Part, s.Answer, s.Verdict,
+			s.Time.Format(time.RFC3339), s.NotBefore.Format(time.RFC3339))
+	}
+
+	return os.WriteFile(h.path, []byte(builder.String()), 0644)
+}
+
+// Check returns an error explaining why an answer should not be submitted at a given time: the part is already solved,
+// the answer was already tried, it falls outside the bounds given by previous answers, or the server asked us to wait.
+func (h *History) Check(day, part int, answer string, now time.Time) error {
+	value, notNumeric := strconv.Atoi(answer)
+	var lower, upper string
+
+	for _, s := range h.submissions {
+		if now.Before(s.NotBefore) {
+			return fmt.Errorf("the server asked to wait until %s", s.NotBefore.Format(time.Kitchen))
+		}
+		if s.Day != day || s.Part != part {
+			continue
+		}
+
+		switch {
+		case s.Verdict == Correct || s.Verdict == AlreadySolved:
+			return fmt.Errorf("day %d part %d is already solved", day, part)
+		case s.Answer == answer && s.Verdict != Wait && s.Verdict != Unrecognised:
+			return fmt.Errorf("%s was already submitted, and it was %s", answer, s.Verdict)
+		}
+
+		// Bounds only make sense for numeric answers.
+		bound, err := strconv.Atoi(s.Answer)
+		if err != nil || notNumeric != nil {
+			continue
+		}
+		if s.Verdict == TooHigh && value >= bound {
+			upper = s.Answer
+		}
+		if s.Verdict == TooLow && value <= bound {
+			lower = s.Answer
+		}
+	}
+
+	if upper != "" {
+		return fmt.Errorf("%s is too high, as %s already was", answer, upper)
+	}
+	if lower != "" {
+		return fmt.Errorf("%s is too low, as %s already was", answer, lower)
+	}
+
+	return nil
+}
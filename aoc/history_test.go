@@ -0,0 +1,68 @@
+package aoc
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistoryCheck(t *testing.T) {
+	now := time.Date(2021, 12, 9, 6, 0, 0, 0, time.UTC)
+	history := &History{submissions: []Submission{
+		{Day: 9, Part: 2, Answer: "500", Verdict: TooLow, Time: now, NotBefore: now.Add(time.Minute)},
+		{Day: 9, Part: 2, Answer: "900", Verdict: TooHigh, Time: now, NotBefore: now},
+		{Day: 9, Part: 2, Answer: "700", Verdict: Wrong, Time: now, NotBefore: now},
+		{Day: 9, Part: 1, Answer: "600", Verdict: Correct, Time: now, NotBefore: now},
+	}}
+	later := now.Add(2 * time.Minute)
+
+	tests := []struct {
+		name   string
+		part   int
+		answer string
+		now    time.Time
+		ok     bool
+	}{
+		{"within bounds", 2, "800", later, true},
+		{"must wait", 2, "800", now, false},
+		{"already submitted", 2, "700", later, false},
+		{"too high", 2, "950", later, false},
+		{"equal to too high", 2, "900", later, false},
+		{"too low", 2, "100", later, false},
+		{"already solved", 1, "601", later, false},
+		{"text answers are not bounded", 2, "LGHEGUEJ", later, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := history.Check(9, tt.part, tt.answer, tt.now)
+			if (err == nil) != tt.ok {
+				t.Errorf("Check(%s) = %v, want ok = %v", tt.answer, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHistorySaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "submissions.txt")
+	now := time.Date(2021, 12, 9, 6, 0, 0, 0, time.UTC)
+	submission := Submission{Day: 9, Part: 2, Answer: "500", Verdict: TooLow, Time: now, NotBefore: now.Add(time.Minute)}
+
+	history, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history.Add(submission)
+	if err := history.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.submissions, []Submission{submission}) {
+		t.Errorf("loaded %+v, want %+v", loaded.submissions, submission)
+	}
+}
@@ -0,0 +1,117 @@
+package aoc
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verdict is the reply of the server to a submitted answer.
+type Verdict int
+
+const (
+	// Unrecognised is used when the reply of the server could not be understood.
+	Unrecognised Verdict = iota
+	Correct
+	TooHigh
+	TooLow
+	// Wrong is an incorrect answer for which the server gave no hint.
+	Wrong
+	// Wait means the answer was not checked, because the previous one was submitted too recently.
+	Wait
+	// AlreadySolved means the answer was not checked, because the part is already solved.
+	AlreadySolved
+)
+
+var verdictNames = []string{"unrecognised", "correct", "too high", "too low", "wrong", "wait", "already solved"}
+
+func (v Verdict) String() string {
+	if int(v) < len(verdictNames) {
+		return verdictNames[v]
+	}
+
+	return fmt.Sprintf("Verdict(%d)", int(v))
+}
+
+// ParseVerdict is the inverse of Verdict.String.
+func ParseVerdict(name string) (Verdict, error) {
+	for i, verdictName := range verdictNames {
+		if verdictName == name {
+			return Verdict(i), nil
+		}
+	}
+
+	return Unrecognised, fmt.Errorf("unknown verdict %q", name)
+}
+
+// Result is the reply of the server to a submitted answer, with the time left to wait before trying again, if any, and
+// the text of the reply.
+type Result struct {
+	Verdict Verdict
+	Wait    time.Duration
+	Message string
+}
+
+var (
+	articleRegexp = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRegexp     = regexp.MustCompile(`<[^>]*>`)
+	waitRegexp    = regexp.MustCompile(`(?:(\d+)m )?(\d+)s left to wait`)
+)
+
+// ParseResponse reads the verdict out of the page returned by the server after submitting an answer.
+func ParseResponse(page string) Result {
+	message := page
+	if match := articleRegexp.FindStringSubmatch(page); match != nil {
+		message = match[1]
+	}
+	message = strings.Join(strings.Fields(html.UnescapeString(tagRegexp.ReplaceAllString(message, ""))), " ")
+	result := Result{Message: message}
+
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		result.Verdict = Correct
+	case strings.Contains(message, "answer is too high"):
+		result.Verdict = TooHigh
+	case strings.Contains(message, "answer is too low"):
+		result.Verdict = TooLow
+	case strings.Contains(message, "That's not the right answer"):
+		result.Verdict = Wrong
+	case strings.Contains(message, "You gave an answer too recently"):
+		result.Verdict = Wait
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		result.Verdict = AlreadySolved
+	}
+
+	// Wrong answers come with a wait too, so we look for it whatever the verdict.
+	if match := waitRegexp.FindStringSubmatch(message); match != nil {
+		minutes, _ := strconv.Atoi("0" + match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		result.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else if strings.Contains(message, "wait one minute") {
+		result.Wait = time.Minute
+	}
+
+	return result
+}
+
+// Submit posts the answer to a part of the puzzle of a given day and returns the verdict of the server.
+func (c *Client) Submit(day, part int, answer string) (Result, error) {
+	form := url.Values{"level": {strconv.Itoa(part)}, "answer": {answer}}
+	request, err := http.NewRequest(http.MethodPost, c.dayURL(day, "/answer"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	page, err := c.do(request)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return ParseResponse(string(page)), nil
+}
@@ -0,0 +1,67 @@
+package aoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func page(message string) string {
+	return "<html><body><main><article><p>" + message + "</p></article></main></body></html>"
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		verdict Verdict
+		wait    time.Duration
+	}{
+		{"correct", "That's the right answer!  You are <em>one gold star</em> closer to saving your vacation.", Correct, 0},
+		{"too high", "That's not the right answer; your answer is too high.  Please wait one minute before trying again.", TooHigh, time.Minute},
+		{"too low", "That's not the right answer; your answer is too low.  Please wait one minute before trying again.", TooLow, time.Minute},
+		{"wrong", "That's not the right answer.  If you're stuck, make sure you're using the full input data.", Wrong, 0},
+		{"wait", "You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 1m 20s left to wait.", Wait, 80 * time.Second},
+		{"already solved", "You don't seem to be solving the right level.  Did you already complete it?", AlreadySolved, 0},
+		{"unrecognised", "Something else entirely.", Unrecognised, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseResponse(page(tt.message))
+			if result.Verdict != tt.verdict || result.Wait != tt.wait {
+				t.Errorf("ParseResponse() = %v, %v, want %v, %v", result.Verdict, result.Wait, tt.verdict, tt.wait)
+			}
+		})
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2021/day/9/answer" || r.FormValue("level") != "2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.FormValue("answer") == "987840" {
+			w.Write([]byte(page("That's the right answer!")))
+		} else {
+			w.Write([]byte(page("That's not the right answer; your answer is too low.")))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+	client.Interval = 0
+
+	for answer, want := range map[string]Verdict{"987840": Correct, "12": TooLow} {
+		result, err := client.Submit(9, 2, answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Verdict != want {
+			t.Errorf("Submit(%s) = %v, want %v", answer, result.Verdict, want)
+		}
+	}
+}
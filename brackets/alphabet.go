@@ -0,0 +1,184 @@
+package brackets
+
+import (
+	"advent_2021/extra"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pair is an opening character together with the one closing its chunks, and the points they are worth when scoring.
+type Pair struct {
+	Open, Close rune
+	// ErrorPoints are given when Close is found where a different character was expected.
+	ErrorPoints int
+	// CompletionPoints are given for each Close needed to complete a line.
+	CompletionPoints int
+}
+
+// Alphabet is a set of pairs of brackets.  Characters which are not part of any pair are skipped, unless the alphabet
+// is strict, so brackets can be checked in any text.
+type Alphabet struct {
+	// Strict makes the characters which are not part of any pair illegal.
+	Strict  bool
+	pairs   []Pair
+	openers map[rune]Pair
+	closers map[rune]Pair
+}
+
+// DefaultConfig describes the four pairs of brackets of the navigation subsystem, with the points of the puzzle, in the
+// format read by LoadAlphabet.
+const DefaultConfig = `# open close error-points completion-points
+( ) 3 1
+[ ] 57 2
+{ } 1197 3
+< > 25137 4
+`
+
+// Default is the alphabet described by DefaultConfig.
+var Default = mustLoadAlphabet(DefaultConfig)
+
+func mustLoadAlphabet(config string) *Alphabet {
+	alphabet, err := LoadAlphabet(strings.NewReader(config))
+	if err != nil {
+		panic(err)
+	}
+
+	return alphabet
+}
+
+// NewAlphabet returns an alphabet made of the given pairs.  Every character can only be used once, either to open or
+// to close chunks.
+func NewAlphabet(pairs []Pair) (*Alphabet, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("the alphabet has no pairs")
+	}
+
+	a := &Alphabet{pairs: append([]Pair(nil), pairs...), openers: make(map[rune]Pair), closers: make(map[rune]Pair)}
+	for _, pair := range pairs {
+		if err := a.add(pair); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+func (a *Alphabet) add(pair Pair) error {
+	for _, r := range []rune{pair.Open, pair.Close} {
+		_, opener := a.openers[r]
+		_, closer := a.closers[r]
+		if opener || closer || pair.Open == pair.Close {
+			return fmt.Errorf("%q is used more than once", r)
+		}
+	}
+
+	a.openers[pair.Open] = pair
+	a.closers[pair.Close] = pair
+	return nil
+}
+
+// ParsePairs returns an alphabet from a string with the opening and closing characters of each pair one after the
+// other, like `()[]`.  The pairs are worth no points.
+func ParsePairs(spec string) (*Alphabet, error) {
+	characters := []rune(spec)
+	if len(characters)%2 != 0 {
+		return nil, fmt.Errorf("%q has an odd number of characters", spec)
+	}
+
+	pairs := make([]Pair, 0, len(characters)/2)
+	for i := 0; i < len(characters); i += 2 {
+		pairs = append(pairs, Pair{Open: characters[i], Close: characters[i+1]})
+	}
+
+	return NewAlphabet(pairs)
+}
+
+// LoadAlphabet reads an alphabet from a configuration with a pair on each line: the opening and closing characters
+// followed by their error and completion points, separated by white space.  Blank lines and lines starting with `#` are
+// ignored.
+func LoadAlphabet(input io.Reader) (*Alphabet, error) {
+	scanner := extra.NewScanner(input)
+	a := &Alphabet{openers: make(map[rune]Pair), closers: make(map[rune]Pair)}
+
+	for scanner.Scan() {
+		line := scanner.Line()
+		if text := strings.TrimSpace(line.Text); text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		pair, err := parsePair(line)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.add(pair); err != nil {
+			return nil, line.Errorf(0, "%v", err)
+		}
+		a.pairs = append(a.pairs, pair)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(a.pairs) == 0 {
+		return nil, scanner.Errorf("the alphabet has no pairs")
+	}
+
+	return a, nil
+}
+
+// parsePair reads a pair from a line of the configuration.
+func parsePair(line extra.Line) (pair Pair, err error) {
+	fields := line.Fields()
+	if len(fields) != 4 {
+		return pair, line.Errorf(0, "expected open, close, error and completion points, found %d fields", len(fields))
+	}
+
+	characters := make([]rune, 2)
+	for i, field := range fields[:2] {
+		if utf8.RuneCountInString(field.Text) != 1 {
+			return pair, field.Errorf(0, "expected a single character, found %q", field.Text)
+		}
+		characters[i], _ = utf8.DecodeRuneInString(field.Text)
+	}
+	pair.Open, pair.Close = characters[0], characters[1]
+
+	if pair.ErrorPoints, err = fields[2].Int(); err != nil {
+		return pair, err
+	}
+	if pair.CompletionPoints, err = fields[3].Int(); err != nil {
+		return pair, err
+	}
+
+	return pair, nil
+}
+
+// Pairs returns the pairs of the alphabet.
+func (a *Alphabet) Pairs() []Pair {
+	return append([]Pair(nil), a.pairs...)
+}
+
+// ErrorScore returns the points of the illegal character of a corrupted line, or 0 for any other line.
+func (a *Alphabet) ErrorScore(r Result) int {
+	if r.Status != Corrupted {
+		return 0
+	}
+
+	return a.closers[r.Found].ErrorPoints
+}
+
+// CompletionScore returns the score of the completion of an incomplete line, where each character multiplies the score
+// so far by 5 and adds its points, or 0 for any other line.
+func (a *Alphabet) CompletionScore(r Result) (score int) {
+	if r.Status != Incomplete {
+		return 0
+	}
+
+	for _, closer := range r.Completion {
+		score = score*5 + a.closers[closer].CompletionPoints
+	}
+
+	return score
+}
@@ -0,0 +1,91 @@
+package brackets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScores(t *testing.T) {
+	tests := []struct {
+		line       string
+		error      int
+		completion int
+	}{
+		{"{([(<{}[<>[]}>{[]{[(<()>", 1197, 0},
+		{"<{([{{}}[<[[[<>{}]]]>[]]", 0, 294},
+		{"())", 0, 0},
+		{"()", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			result := Default.Check(tt.line)
+			if got := Default.ErrorScore(result); got != tt.error {
+				t.Errorf("ErrorScore() = %d, want %d", got, tt.error)
+			}
+			if got := Default.CompletionScore(result); got != tt.completion {
+				t.Errorf("CompletionScore() = %d, want %d", got, tt.completion)
+			}
+		})
+	}
+}
+
+func TestParsePairs(t *testing.T) {
+	alphabet, err := ParsePairs("«»ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Columns are counted in bytes, and « takes two of them.
+	want := Result{Status: Corrupted, Column: 6, Found: '»', Expected: 'b'}
+	if got := alphabet.Check("«aab»"); got != want {
+		t.Errorf("Check() = %+v, want %+v", got, want)
+	}
+	alphabet.Strict = true
+	if got := alphabet.Check("(a)"); got.Status != Unknown || got.Column != 1 {
+		t.Errorf("Check() = %+v, want an unknown character at column 1", got)
+	}
+
+	for _, spec := range []string{"", "()(", "(()]", "aa"} {
+		if _, err := ParsePairs(spec); err == nil {
+			t.Errorf("ParsePairs(%q) accepted an invalid alphabet", spec)
+		}
+	}
+}
+
+func TestLoadAlphabet(t *testing.T) {
+	alphabet, err := LoadAlphabet(strings.NewReader("# A custom alphabet.\n\n/ \\ 10 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := alphabet.CompletionScore(alphabet.Check("//")); got != 12 {
+		t.Errorf("CompletionScore() = %d, want 12", got)
+	}
+
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"", "input:0:1: the alphabet has no pairs"},
+		{"( ) 1\n", "input:1:1: expected open, close, error and completion points, found 3 fields"},
+		{"( )) 1 1\n", "input:1:3: expected a single character, found \"))\""},
+		{"( ) one 1\n", "input:1:5: invalid number \"one\": invalid syntax"},
+		{"( ) 1 1\n[ ( 1 1\n", "input:2:1: '(' is used more than once"},
+	}
+
+	for _, tt := range tests {
+		if _, err := LoadAlphabet(strings.NewReader(tt.config)); err == nil || err.Error() != tt.want {
+			t.Errorf("LoadAlphabet(%q) error = %v, want %s", tt.config, err, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	if got, want := Check("<(]").Message(), "expected ')' but found ']'"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	if got := Check("<>").Message(); got != "" {
+		t.Errorf("Message() of a valid line = %q, want nothing", got)
+	}
+}
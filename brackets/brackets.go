@@ -0,0 +1,127 @@
+// Package brackets checks that the chunks of a line, delimited by pairs of brackets, are properly opened and closed.
+package brackets
+
+import (
+	"advent_2021/dataStructures"
+	"fmt"
+)
+
+// Status classifies a line by the way its chunks are opened and closed.
+type Status int
+
+const (
+	// Valid lines close every chunk they open.
+	Valid Status = iota
+	// Corrupted lines close a chunk with the wrong character.
+	Corrupted
+	// Incomplete lines leave some chunks open.
+	Incomplete
+	// OverClosed lines close a chunk which was never opened.
+	OverClosed
+	// Unknown lines contain a character which is not part of any pair, which is only illegal for strict alphabets.
+	Unknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case Valid:
+		return "valid"
+	case Corrupted:
+		return "corrupted"
+	case Incomplete:
+		return "incomplete"
+	case OverClosed:
+		return "over-closed"
+	case Unknown:
+		return "unknown character"
+	default:
+		return "invalid status"
+	}
+}
+
+// Result describes the outcome of checking a line.  Column, Found and Expected point at the first illegal character of
+// corrupted, over-closed and unknown lines, with Expected left as 0 when no closing character was expected.  For
+// incomplete lines, Column points right after the end of the line, Expected is the first character missing, and
+// Completion holds the characters closing every chunk left open.
+type Result struct {
+	Status     Status
+	Column     int
+	Found      rune
+	Expected   rune
+	Completion string
+}
+
+// Message describes the problem found in a line, in the style of a compiler diagnostic, or returns an empty string for
+// valid lines.
+func (r Result) Message() string {
+	switch r.Status {
+	case Corrupted:
+		return fmt.Sprintf("expected %q but found %q", r.Expected, r.Found)
+	case Incomplete:
+		return fmt.Sprintf("expected %q but found the end of the line, missing %q", r.Expected, r.Completion)
+	case OverClosed:
+		return fmt.Sprintf("found %q but there is no chunk to close", r.Found)
+	case Unknown:
+		return fmt.Sprintf("unknown character %q", r.Found)
+	default:
+		return ""
+	}
+}
+
+// chunk is a chunk opened and not closed yet.
+type chunk struct {
+	closer rune
+	column int
+}
+
+// Check goes through a line using the Default alphabet.
+func Check(line string) Result {
+	return Default.Check(line)
+}
+
+// Check goes through a line, stopping at its first illegal character.  Columns start at 1 and are counted in bytes.
+// Characters which are not part of any pair are skipped, unless the alphabet is strict.
+func (a *Alphabet) Check(line string) Result {
+	result, _ := a.walk(line, nil)
+	return result
+}
+
+// walk goes through a line like Check, calling visit with the number of chunks open after each character, and returns
+// the chunks left open, innermost first.
+func (a *Alphabet) walk(line string, visit func(depth int)) (Result, []chunk) {
+	var open dataStructures.Stack[chunk]
+
+	for i, r := range line {
+		column := i + 1
+
+		if pair, ok := a.openers[r]; ok {
+			open.Push(chunk{closer: pair.Close, column: column})
+		} else if _, ok := a.closers[r]; !ok {
+			if a.Strict {
+				return Result{Status: Unknown, Column: column, Found: r}, nil
+			}
+		} else if expected, ok := open.Pop(); !ok {
+			return Result{Status: OverClosed, Column: column, Found: r}, nil
+		} else if r != expected.closer {
+			return Result{Status: Corrupted, Column: column, Found: r, Expected: expected.closer}, nil
+		}
+
+		if visit != nil {
+			visit(open.Len())
+		}
+	}
+
+	if open.Len() == 0 {
+		return Result{Status: Valid}, nil
+	}
+
+	pending := make([]chunk, 0, open.Len())
+	completion := make([]rune, 0, open.Len())
+	for open.Len() > 0 {
+		c, _ := open.Pop()
+		pending = append(pending, c)
+		completion = append(completion, c.closer)
+	}
+
+	return Result{Status: Incomplete, Column: len(line) + 1, Expected: completion[0], Completion: string(completion)}, pending
+}
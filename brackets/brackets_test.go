@@ -0,0 +1,37 @@
+package brackets
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		line string
+		want Result
+	}{
+		{"", Result{Status: Valid}},
+		{"([]){<>}", Result{Status: Valid}},
+		{"{([(<{}[<>[]}>{[]{[(<()>", Result{Status: Corrupted, Column: 13, Found: '}', Expected: ']'}},
+		{"[[<[([]))<([[{}[[()]]]", Result{Status: Corrupted, Column: 9, Found: ')', Expected: ']'}},
+		{"[({(<(())[]>[[{[]{<()<>>", Result{Status: Incomplete, Column: 25, Expected: '}', Completion: "}}]])})]"}},
+		{"<{([", Result{Status: Incomplete, Column: 5, Expected: ']', Completion: "])}>"}},
+		{"()]", Result{Status: OverClosed, Column: 3, Found: ']'}},
+		{")", Result{Status: OverClosed, Column: 1, Found: ')'}},
+		{"(a)", Result{Status: Valid}},
+		{"foo(bar[1])", Result{Status: Valid}},
+		{"f(x[1)]", Result{Status: Corrupted, Column: 6, Found: ')', Expected: ']'}},
+		{"if (a < b", Result{Status: Incomplete, Column: 10, Expected: '>', Completion: ">)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := Check(tt.line); got != tt.want {
+				t.Errorf("Check(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	strict := *Default
+	strict.Strict = true
+	if got, want := strict.Check("(a)"), (Result{Status: Unknown, Column: 2, Found: 'a'}); got != want {
+		t.Errorf("strict Check() = %+v, want %+v", got, want)
+	}
+}
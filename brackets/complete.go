@@ -0,0 +1,53 @@
+package brackets
+
+import "fmt"
+
+// SyntaxError is returned when completing a line which cannot be completed, because it is corrupted, over-closed or has
+// unknown characters.
+type SyntaxError struct {
+	Result Result
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Result.Column, e.Result.Message())
+}
+
+// Completion describes how a line can be completed.
+type Completion struct {
+	// Suffix is the shortest string closing every chunk left open, which is empty for valid lines.
+	Suffix string
+	// Depth holds the number of chunks open after each character of the line.
+	Depth []int
+	// Open holds the columns where each chunk left open was started, from the outermost to the innermost one, so the
+	// chunk started at Open[i] is closed by the character len(Open)-1-i of Suffix.
+	Open []int
+}
+
+// Complete returns the completion of a line using the Default alphabet.
+func Complete(line string) (Completion, error) {
+	return Default.Complete(line)
+}
+
+// Complete returns the completion of a line, or a SyntaxError if it cannot be completed.
+func (a *Alphabet) Complete(line string) (Completion, error) {
+	var completion Completion
+
+	result, pending := a.walk(line, func(depth int) {
+		completion.Depth = append(completion.Depth, depth)
+	})
+	switch result.Status {
+	case Valid:
+		return completion, nil
+	case Incomplete:
+	default:
+		return Completion{}, &SyntaxError{Result: result}
+	}
+
+	completion.Suffix = result.Completion
+	completion.Open = make([]int, len(pending))
+	for i, c := range pending {
+		completion.Open[len(pending)-1-i] = c.column
+	}
+
+	return completion, nil
+}
@@ -0,0 +1,60 @@
+package brackets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		line string
+		want Completion
+	}{
+		{"", Completion{}},
+		{"()", Completion{Depth: []int{1, 0}}},
+		{"[(<>", Completion{Suffix: ")]", Depth: []int{1, 2, 3, 2}, Open: []int{1, 2}}},
+		{"{}(<", Completion{Suffix: ">)", Depth: []int{1, 0, 1, 2}, Open: []int{3, 4}}},
+		{"[({(<(())[]>[[{[]{<()<>>", Completion{
+			Suffix: "}}]])})]",
+			Depth:  []int{1, 2, 3, 4, 5, 6, 7, 6, 5, 6, 5, 4, 5, 6, 7, 8, 7, 8, 9, 10, 9, 10, 9, 8},
+			Open:   []int{1, 2, 3, 4, 13, 14, 15, 18},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := Complete(tt.line)
+			if err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteSyntaxError(t *testing.T) {
+	strict := *Default
+	strict.Strict = true
+
+	for _, line := range []string{"(]", "())", "(a"} {
+		_, err := strict.Complete(line)
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Complete(%q) error = %v, want a SyntaxError", line, err)
+		}
+	}
+
+	// Other characters are skipped, but still get a depth.
+	if got, err := Complete("(a"); err != nil || got.Suffix != ")" || len(got.Depth) != 2 {
+		t.Errorf("Complete() = %+v, %v, want the suffix ) and 2 depths", got, err)
+	}
+
+	_, err := Complete("<(]")
+	if want := "column 3: expected ')' but found ']'"; err == nil || err.Error() != want {
+		t.Errorf("Complete() error = %v, want %s", err, want)
+	}
+}
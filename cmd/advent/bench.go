@@ -0,0 +1,241 @@
+package main
+
+import (
+	"advent_2021/solver"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"text/tabwriter"
+	"time"
+)
+
+// measurement is the benchmark result of a phase of a day, either `parse`, `part1` or `part2`.
+type measurement struct {
+	Day         int    `json:"day"`
+	Phase       string `json:"phase"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+	PeakBytes   uint64 `json:"peak_bytes"`
+}
+
+func benchCommand(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	daySpec := flags.String("day", "", "day or days to benchmark, e.g. `9`, `1-5` or `1,3,10-14` (default all)")
+	all := flags.Bool("all", false, "benchmark every registered day")
+	save := flags.String("save", "", "write the measurements to this `file`, to be used later as a baseline")
+	baselinePath := flags.String("baseline", "", "compare the measurements with the ones saved in this `file`")
+	threshold := flags.Float64("threshold", 10, "`percentage` of slowdown, or allocation growth, flagged as a regression")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	days, err := selectDays(*daySpec, *all || *daySpec == "")
+	if err != nil {
+		return err
+	}
+
+	var baseline []measurement
+	if *baselinePath != "" {
+		if baseline, err = loadMeasurements(*baselinePath); err != nil {
+			return err
+		}
+	}
+
+	measurements := make([]measurement, 0)
+	for _, day := range days {
+		dayMeasurements, err := benchDay(day)
+		if err != nil {
+			return err
+		}
+		measurements = append(measurements, dayMeasurements...)
+	}
+
+	regressions := printMeasurements(measurements, baseline, *threshold)
+
+	if *save != "" {
+		if err := saveMeasurements(*save, measurements); err != nil {
+			return err
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d phases regressed more than %.f%%", regressions, *threshold)
+	}
+
+	return nil
+}
+
+// benchDay benchmarks parsing the default input of a day and solving each of its parts.
+func benchDay(day int) ([]measurement, error) {
+	factory := func() solver.Solver {
+		s, _ := solver.New(day)
+		return s
+	}
+	if factory() == nil {
+		return nil, fmt.Errorf("day %d is not registered", day)
+	}
+
+	input, err := os.ReadFile(solver.InputPath(day))
+	if err != nil {
+		return nil, err
+	}
+
+	// We solve everything once first, both to fail early on bad inputs and to find out the peak memory of each phase.
+	// Parts which are not solved yet are left out.
+	s := factory()
+	parsePeak, err := peakMemory(func() error { return s.Parse(bytes.NewReader(input)) })
+	if err != nil {
+		return nil, fmt.Errorf("day %d: %w", day, err)
+	}
+	peaks, solved := []uint64{parsePeak}, []bool{true}
+	for part := 1; part <= 2; part++ {
+		peak, err := peakMemory(func() error {
+			_, err := solver.Solve(s, part)
+			return err
+		})
+		if err != nil && !errors.Is(err, solver.ErrNotSolved) {
+			return nil, fmt.Errorf("day %d part %d: %w", day, part, err)
+		}
+		peaks, solved = append(peaks, peak), append(solved, err == nil)
+	}
+
+	benchmarks := []func(b *testing.B){
+		solver.ParseBenchmark(factory, input),
+		solver.PartBenchmark(factory, input, 1),
+		solver.PartBenchmark(factory, input, 2),
+	}
+	result := make([]measurement, 0, len(benchmarks))
+	for i, benchmark := range benchmarks {
+		if !solved[i] {
+			continue
+		}
+
+		r := testing.Benchmark(benchmark)
+		if r.N == 0 {
+			return nil, fmt.Errorf("day %d: benchmark %d failed", day, i)
+		}
+
+		result = append(result, measurement{
+			Day:         day,
+			Phase:       []string{"parse", "part1", "part2"}[i],
+			NsPerOp:     r.NsPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+			PeakBytes:   peaks[i],
+		})
+	}
+
+	return result, nil
+}
+
+// peakMemory runs a function once and returns how much the heap grew at most while it was running, with the garbage
+// collector working as usual.  The heap is sampled in the background, and once more at the end, so peaks shorter than
+// the sampling interval might be missed, and the result changes a little from run to run.
+func peakMemory(f func() error) (uint64, error) {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	read := func() uint64 {
+		metrics.Read(sample)
+		return sample[0].Value.Uint64()
+	}
+
+	runtime.GC()
+	base, peak := read(), uint64(0)
+	update := func() {
+		if current := read(); current > base && current-base > peak {
+			peak = current - base
+		}
+	}
+	done, finished := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		for {
+			update()
+			select {
+			case <-done:
+				return
+			case <-time.After(50 * time.Microsecond):
+			}
+		}
+	}()
+
+	err := f()
+	close(done)
+	<-finished
+	update()
+
+	return peak, err
+}
+
+// printMeasurements writes the measurements as a table, comparing them with the baseline when there is one, and
+// returns the number of regressions found.
+func printMeasurements(measurements, baseline []measurement, threshold float64) (regressions int) {
+	previous := make(map[string]measurement)
+	for _, m := range baseline {
+		previous[fmt.Sprintf("%d/%s", m.Day, m.Phase)] = m
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "DAY\tPHASE\tNS/OP\tALLOCS/OP\tB/OP\tPEAK B\tΔ NS/OP\tΔ ALLOCS\tSTATUS\t")
+
+	for _, m := range measurements {
+		delta, status := "\t", ""
+		if old, ok := previous[fmt.Sprintf("%d/%s", m.Day, m.Phase)]; ok {
+			timeDelta := percentage(old.NsPerOp, m.NsPerOp)
+			allocsDelta := percentage(old.AllocsPerOp, m.AllocsPerOp)
+			delta = fmt.Sprintf("%+.1f%%\t%+.1f%%", timeDelta, allocsDelta)
+			if timeDelta > threshold || allocsDelta > threshold {
+				status = "REGRESSION"
+				regressions++
+			}
+		}
+
+		fmt.Fprintf(writer, "%d\t%s\t%d\t%d\t%d\t%d\t%s\t%s\t\n",
+			m.Day, m.Phase, m.NsPerOp, m.AllocsPerOp, m.BytesPerOp, m.PeakBytes, delta, status)
+	}
+	writer.Flush()
+
+	return regressions
+}
+
+// percentage returns the relative change between two values, in percent.
+func percentage(old, current int64) float64 {
+	if old == 0 {
+		if current == 0 {
+			return 0
+		}
+		return 100
+	}
+
+	return float64(current-old) * 100 / float64(old)
+}
+
+func loadMeasurements(path string) (result []measurement, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("the baseline is empty")
+	}
+
+	return result, nil
+}
+
+func saveMeasurements(path string, measurements []measurement) error {
+	content, err := json.MarshalIndent(measurements, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	day12 "advent_2021/12"
+	"advent_2021/solver"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func cavesCommand(args []string) error {
+	flags := flag.NewFlagSet("caves", flag.ContinueOnError)
+	input := flags.String("input", solver.InputPath(12), "read the caves from this `file`, - reads stdin")
+	part := flags.Int("part", 1, "start from the rules of this part, 1 or 2")
+	smallVisits := flags.Int("small-visits", 0, "visit each small cave up to this `number` of times")
+	revisits := flags.Int("revisits", 0, "let this `number` of small caves be visited more times")
+	revisitVisits := flags.Int("revisit-visits", 0, "visit the revisited small caves up to this `number` of times")
+	largeVisits := flags.Int("large-visits", 0, "visit each large cave up to this `number` of times (0 means no limit)")
+	forbid := flags.String("forbid", "", "never visit these `caves`, separated by commas")
+	count := flags.Bool("count", false, "print the number of paths instead of listing them")
+	limit := flags.Int("limit", 0, "stop after this `number` of paths (0 lists them all)")
+	minLength := flags.Int("min-length", 0, "only list paths with at least this number of caves")
+	maxLength := flags.Int("max-length", 0, "only list paths with at most this number of caves (0 means no maximum)")
+	through := flags.String("through", "", "only list paths visiting all these `caves`, separated by commas")
+	avoid := flags.String("avoid", "", "only list paths visiting none of these `caves`, separated by commas")
+	dot := flags.Bool("dot", false, "write the cave system as a Graphviz graph instead of listing paths")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	puzzle := &day12.Puzzle{}
+	if _, err := parseInput(puzzle, *input); err != nil {
+		return err
+	}
+
+	if *dot {
+		return puzzle.WriteDOT(os.Stdout)
+	}
+
+	var rules day12.Rules
+	switch *part {
+	case 1:
+		rules = day12.PartOneRules
+	case 2:
+		rules = day12.PartTwoRules
+	default:
+		return fmt.Errorf("invalid part %d, it must be 1 or 2", *part)
+	}
+
+	// The rules given explicitly replace those of the part.
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "small-visits":
+			rules.SmallVisits = *smallVisits
+		case "revisits":
+			rules.Revisits = *revisits
+		case "revisit-visits":
+			rules.RevisitVisits = *revisitVisits
+		case "large-visits":
+			rules.LargeVisits = *largeVisits
+		case "forbid":
+			rules.Forbidden = splitList(*forbid)
+		}
+	})
+
+	if *count {
+		paths, err := puzzle.CountPaths(rules)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(paths)
+		return nil
+	}
+
+	filter := day12.PathFilter{
+		MinLength: *minLength,
+		MaxLength: *maxLength,
+		Through:   splitList(*through),
+		Avoid:     splitList(*avoid),
+	}
+	_, err := puzzle.WritePaths(os.Stdout, rules, filter, *limit)
+	return err
+}
+
+// splitList splits a list of values separated by commas, where an empty string is an empty list.
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	return strings.Split(list, ",")
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"advent_2021/brackets"
+	"advent_2021/extra"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// checkSummary counts the lines checked by status, and keeps their scores.
+type checkSummary struct {
+	lines            int
+	statuses         map[brackets.Status]int
+	errorScore       int
+	completionScores []int
+}
+
+func checkCommand(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	pairs := flags.String("pairs", "", "opening and closing characters of each pair, e.g. `()[]` (default the puzzle ones)")
+	config := flags.String("config", "", "`file` with the pairs and their points, one `open close error completion` per line")
+	strict := flags.Bool("strict", false, "report characters which are not part of any pair, instead of skipping them")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	alphabet, err := loadAlphabet(*pairs, *config, *strict)
+	if err != nil {
+		return err
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	summary := checkSummary{statuses: make(map[brackets.Status]int)}
+	for _, path := range paths {
+		if err := checkFile(alphabet, path, &summary); err != nil {
+			return err
+		}
+	}
+
+	bad := summary.lines - summary.statuses[brackets.Valid]
+	fmt.Printf("%d lines checked: %d valid, %d corrupted, %d incomplete, %d over-closed, %d with unknown characters.\n",
+		summary.lines, summary.statuses[brackets.Valid], summary.statuses[brackets.Corrupted],
+		summary.statuses[brackets.Incomplete], summary.statuses[brackets.OverClosed], summary.statuses[brackets.Unknown])
+	fmt.Printf("Syntax error score: %d, middle completion score: %d.\n", summary.errorScore, summary.middleCompletionScore())
+
+	if bad > 0 {
+		return fmt.Errorf("found bad brackets in %d lines", bad)
+	}
+	return nil
+}
+
+// loadAlphabet returns the alphabet given either as pairs or as a configuration file, or a copy of the default one, and
+// makes it strict when asked to.
+func loadAlphabet(pairs string, config string, strict bool) (alphabet *brackets.Alphabet, err error) {
+	switch {
+	case pairs != "" && config != "":
+		return nil, errors.New("-pairs and -config cannot be used together")
+	case pairs != "":
+		alphabet, err = brackets.ParsePairs(pairs)
+	case config != "":
+		var file *os.File
+		if file, err = os.Open(config); err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		alphabet, err = brackets.LoadAlphabet(file)
+	default:
+		copied := *brackets.Default
+		alphabet = &copied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	alphabet.Strict = strict
+	return alphabet, nil
+}
+
+// checkFile checks every line of a file, where `-` reads the standard input, printing a diagnostic for each bad one.
+func checkFile(alphabet *brackets.Alphabet, path string, summary *checkSummary) error {
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	scanner := extra.NewScanner(input)
+	for scanner.Scan() {
+		line := scanner.Line()
+		result := alphabet.Check(line.Text)
+
+		summary.lines++
+		summary.statuses[result.Status]++
+		summary.errorScore += alphabet.ErrorScore(result)
+		if result.Status == brackets.Incomplete {
+			summary.completionScores = append(summary.completionScores, alphabet.CompletionScore(result))
+		}
+
+		if result.Status != brackets.Valid {
+			fmt.Println(line.Errorf(result.Column-1, "%s", result.Message()))
+		}
+	}
+
+	return scanner.Err()
+}
+
+// middleCompletionScore returns the median of the completion scores, or 0 when no line was incomplete.
+func (s *checkSummary) middleCompletionScore() int {
+	if len(s.completionScores) == 0 {
+		return 0
+	}
+
+	scores := append([]int(nil), s.completionScores...)
+	sort.Ints(scores)
+
+	return scores[len(scores)/2]
+}
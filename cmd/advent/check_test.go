@@ -0,0 +1,37 @@
+package main
+
+import (
+	"advent_2021/brackets"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckFileMixedText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.dsl")
+	if err := os.WriteFile(path, []byte("foo(bar[1])\nx = [1, 2)\nwhen (a and [b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, strict := range []bool{false, true} {
+		alphabet, err := loadAlphabet("()[]", "", strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		summary := checkSummary{statuses: make(map[brackets.Status]int)}
+		if err := checkFile(alphabet, path, &summary); err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[brackets.Status]int{brackets.Valid: 1, brackets.Corrupted: 1, brackets.Incomplete: 1}
+		if strict {
+			want = map[brackets.Status]int{brackets.Unknown: 3}
+		}
+		for status, count := range want {
+			if summary.statuses[status] != count {
+				t.Errorf("strict %v: %d %v lines, want %d", strict, summary.statuses[status], status, count)
+			}
+		}
+	}
+}
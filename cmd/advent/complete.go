@@ -0,0 +1,55 @@
+package main
+
+import (
+	"advent_2021/brackets"
+	"advent_2021/extra"
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func completeCommand(args []string) error {
+	flags := flag.NewFlagSet("complete", flag.ContinueOnError)
+	pairs := flags.String("pairs", "", "opening and closing characters of each pair, e.g. `()[]` (default the puzzle ones)")
+	config := flags.String("config", "", "`file` with the pairs and their points, one `open close error completion` per line")
+	strict := flags.Bool("strict", false, "refuse lines with characters which are not part of any pair")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	alphabet, err := loadAlphabet(*pairs, *config, *strict)
+	if err != nil {
+		return err
+	}
+
+	// Lines which cannot be completed are reported on the standard error and left out of the output.
+	failed := 0
+	output := bufio.NewWriter(os.Stdout)
+	scanner := extra.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := scanner.Line()
+
+		completion, err := alphabet.Complete(line.Text)
+		var syntaxErr *brackets.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			fmt.Fprintln(os.Stderr, line.Errorf(syntaxErr.Result.Column-1, "%s", syntaxErr.Result.Message()))
+			failed++
+			continue
+		}
+
+		fmt.Fprintln(output, line.Text+completion.Suffix)
+	}
+	if err := output.Flush(); err != nil {
+		return err
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d lines could not be completed", failed)
+	}
+	return nil
+}
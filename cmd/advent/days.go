@@ -0,0 +1,20 @@
+package main
+
+// Every day registers its exercises in the solver registry when its package is initialised, so importing them here is
+// enough to make them available to the runner.
+import (
+	_ "advent_2021/01"
+	_ "advent_2021/02"
+	_ "advent_2021/03"
+	_ "advent_2021/04"
+	_ "advent_2021/05"
+	_ "advent_2021/06"
+	_ "advent_2021/07"
+	_ "advent_2021/08"
+	_ "advent_2021/09"
+	_ "advent_2021/10"
+	_ "advent_2021/11"
+	_ "advent_2021/12"
+	_ "advent_2021/13"
+	_ "advent_2021/14"
+)
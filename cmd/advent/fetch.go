@@ -0,0 +1,48 @@
+package main
+
+import (
+	"advent_2021/aoc"
+	"advent_2021/solver"
+	"flag"
+	"fmt"
+)
+
+func fetchCommand(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	daySpec := flags.String("day", "", "day or days to fetch, e.g. `9`, `1-5` or `1,3,10-14`")
+	all := flags.Bool("all", false, "fetch the input of every registered day")
+	baseURL := flags.String("base-url", "", "`address` of the puzzle server (default $"+aoc.BaseURLEnv+" or "+aoc.DefaultBaseURL+")")
+	sessionFile := flags.String("session-file", "", "`file` with the session token, used when $"+aoc.SessionEnv+" is not set")
+	interval := flags.Duration("interval", aoc.DefaultInterval, "minimum `time` between two requests")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	days, err := selectDays(*daySpec, *all)
+	if err != nil {
+		return err
+	}
+
+	session, err := aoc.LoadSession(*sessionFile)
+	if err != nil {
+		return err
+	}
+	client := aoc.NewClient(*baseURL, session)
+	client.Interval = *interval
+
+	for _, day := range days {
+		path := solver.InputPath(day)
+		downloaded, err := client.CacheInput(day, path)
+		if err != nil {
+			return fmt.Errorf("day %d: %w", day, err)
+		}
+
+		if downloaded {
+			fmt.Printf("Day %d: downloaded to %s.\n", day, path)
+		} else {
+			fmt.Printf("Day %d: %s already exists, skipping.\n", day, path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	day13 "advent_2021/13"
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func foldCommand(args []string) error {
+	flags := flag.NewFlagSet("fold", flag.ContinueOnError)
+	input := flags.String("input", solver.InputPath(13), "read the paper from this `file`, - reads stdin")
+	pngPrefix := flags.String("png", "", "draw each step as a PNG image named after this `prefix`, like prefix-01.png")
+	svgPrefix := flags.String("svg", "", "draw each step as an SVG image named after this `prefix`, like prefix-01.svg")
+	scale := flags.Int("scale", 1, "draw each cell of the images as a square of this `size`")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	puzzle := &day13.Puzzle{}
+	if _, err := parseInput(puzzle, *input); err != nil {
+		return err
+	}
+
+	return puzzle.Fold(func(step int, order day13.Order, dots *grid.Grid[bool]) error {
+		exports := []struct {
+			prefix, extension string
+			write             func(w io.Writer, g *grid.Grid[int], palette picture.Palette, scale int) error
+		}{
+			{*pngPrefix, "png", picture.WritePNG},
+			{*svgPrefix, "svg", picture.WriteSVG},
+		}
+
+		exported := false
+		for _, export := range exports {
+			if export.prefix == "" {
+				continue
+			}
+
+			var content bytes.Buffer
+			if err := export.write(&content, picture.Bools(dots), picture.Mono, *scale); err != nil {
+				return err
+			}
+			path := fmt.Sprintf("%s-%02d.%s", export.prefix, step, export.extension)
+			if err := os.WriteFile(path, content.Bytes(), 0644); err != nil {
+				return err
+			}
+			exported = true
+		}
+
+		if exported {
+			return nil
+		}
+		fmt.Printf("Step %d, %v:\n", step, order)
+		if err := dots.Print(os.Stdout, dotCell); err != nil {
+			return err
+		}
+		fmt.Println()
+		return nil
+	})
+}
+
+// dotCell draws a cell of the paper.
+func dotCell(dot bool) string {
+	if dot {
+		return "#"
+	}
+	return "."
+}
+
+func unfoldCommand(args []string) error {
+	flags := flag.NewFlagSet("unfold", flag.ContinueOnError)
+	patternPath := flags.String("pattern", "", "read the folded paper from this `file`, drawn with # and .")
+	folds := flags.String("folds", solver.InputPath(13), "take the folding instructions from this puzzle input `file`")
+	limit := flags.Int("limit", 1, "write at most this `number` of papers (0 writes them all)")
+	count := flags.Bool("count", false, "print the number of papers instead of writing them")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *patternPath == "" {
+		return errors.New("-pattern is required")
+	}
+
+	pattern, err := readPattern(*patternPath)
+	if err != nil {
+		return err
+	}
+
+	puzzle := &day13.Puzzle{}
+	if _, err := parseInput(puzzle, *folds); err != nil {
+		return err
+	}
+	unfolding := day13.Unfold(pattern, puzzle.Orders())
+
+	if *count {
+		fmt.Println(unfolding.Count())
+		return nil
+	}
+
+	// Every paper is written as a puzzle input, separated from the next one by a blank line.
+	written := 0
+	unfolding.PreImages(func(paper *day13.Paper) bool {
+		if written > 0 {
+			fmt.Println()
+		}
+		if err = paper.WriteInput(os.Stdout, puzzle.Orders()); err != nil {
+			return false
+		}
+
+		written++
+		return *limit == 0 || written < *limit
+	})
+	if err == nil && written == 0 {
+		return errors.New("no paper folds into the pattern following these instructions")
+	}
+
+	return err
+}
+
+// readPattern reads the drawing of a folded paper from a file.
+func readPattern(path string) (*day13.Paper, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return day13.ReadPattern(file)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// record is the structured form of a result, as written in the json and csv formats.
+type record struct {
+	Day        int    `json:"day"`
+	Part       int    `json:"part"`
+	Answer     string `json:"answer"`
+	DurationNs int64  `json:"duration_ns"`
+	Input      string `json:"input"`
+	InputHash  string `json:"input_sha256"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newRecord(r result) record {
+	rec := record{
+		Day:        r.day,
+		Part:       r.part,
+		Answer:     r.answer.String(),
+		DurationNs: r.duration.Nanoseconds(),
+		Input:      r.input,
+		InputHash:  r.inputHash,
+	}
+	if r.err != nil {
+		rec.Answer, rec.Error = "", r.err.Error()
+	}
+
+	return rec
+}
+
+// resultWriter returns the function writing results in the given format: an aligned table for text, one object per
+// line for json, or a csv file with a header.
+func resultWriter(format string) (func(w io.Writer, results []result) error, error) {
+	switch format {
+	case "text":
+		return writeTable, nil
+	case "json":
+		return writeJSON, nil
+	case "csv":
+		return writeCSV, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, it must be text, json or csv", format)
+	}
+}
+
+func writeTable(w io.Writer, results []result) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DAY\tPART\tANSWER\tTIME")
+
+	for _, r := range results {
+		answer := r.answer.String()
+		if r.err != nil {
+			answer = "error: " + r.err.Error()
+		}
+		fmt.Fprintf(writer, "%d\t%d\t%s\t%s\n", r.day, r.part, answer, r.duration.Round(time.Microsecond))
+	}
+
+	return writer.Flush()
+}
+
+func writeJSON(w io.Writer, results []result) error {
+	encoder := json.NewEncoder(w)
+
+	for _, r := range results {
+		if err := encoder.Encode(newRecord(r)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeCSV(w io.Writer, results []result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"day", "part", "answer", "duration_ns", "input", "input_sha256", "error"}); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		rec := newRecord(r)
+		err := writer.Write([]string{
+			strconv.Itoa(rec.Day),
+			strconv.Itoa(rec.Part),
+			rec.Answer,
+			strconv.FormatInt(rec.DurationNs, 10),
+			rec.Input,
+			rec.InputHash,
+			rec.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,108 @@
+// Command advent runs the solutions to the Advent of Code 2021 puzzles stored in this repository.
+//
+// Usage:
+//
+//	advent run -day 9 -part 2
+//	advent run -day 1-5,9
+//	advent run -all
+//	advent run -all -format json
+//	advent verify -day 9
+//	advent bench -day 12 -baseline bench.json
+//	advent caves -part 2 -limit 10 -through b
+//	advent caves -dot | dot -Tsvg > caves.svg
+//	advent check -pairs '()[]' my_file.txt
+//	advent complete < my_file.txt
+//	advent fetch -day 15
+//	advent fold -png steps
+//	advent new -day 15
+//	advent submit -day 15 -part 1
+//	advent unfold -pattern code.txt -limit 5
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command is a subcommand of the runner, it receives the arguments following its name.
+type command func(args []string) error
+
+var commands = map[string]command{
+	"bench":    benchCommand,
+	"caves":    cavesCommand,
+	"check":    checkCommand,
+	"complete": completeCommand,
+	"fetch":    fetchCommand,
+	"fold":     foldCommand,
+	"new":      newCommand,
+	"run":      runCommand,
+	"submit":   submitCommand,
+	"unfold":   unfoldCommand,
+	"verify":   verifyCommand,
+}
+
+// flagError is an error of the flag package, which has already reported it together with the usage of the command.
+type flagError struct {
+	err error
+}
+
+func (e flagError) Error() string {
+	return e.err.Error()
+}
+
+func (e flagError) Unwrap() error {
+	return e.err
+}
+
+// parseFlags parses the flags of a command, telling apart the errors the flag package has already reported.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return flagError{err: err}
+	}
+
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: advent <command> [flags]\n\nCommands:\n")
+	fmt.Fprintf(os.Stderr, "  bench     benchmark the selected days, optionally comparing them with a baseline\n")
+	fmt.Fprintf(os.Stderr, "  caves     list the paths through the caves of day 12, or draw them as a Graphviz graph\n")
+	fmt.Fprintf(os.Stderr, "  check     report the lines with unbalanced brackets of the given files\n")
+	fmt.Fprintf(os.Stderr, "  complete  close the chunks left open by each line of the standard input\n")
+	fmt.Fprintf(os.Stderr, "  fetch     download the inputs of the selected days that are not in inputs/ yet\n")
+	fmt.Fprintf(os.Stderr, "  fold      show the paper of day 13 after each fold, or draw each step as an image\n")
+	fmt.Fprintf(os.Stderr, "  new       create the package, example and input placeholder of a new day\n")
+	fmt.Fprintf(os.Stderr, "  run       solve the selected days and print a table with the results\n")
+	fmt.Fprintf(os.Stderr, "  submit    send the answer of a part to the puzzle server\n")
+	fmt.Fprintf(os.Stderr, "  unfold    write papers of day 13 which fold into a pattern, as puzzle inputs\n")
+	fmt.Fprintf(os.Stderr, "  verify    check the answers of the selected days against the accepted ones\n")
+	fmt.Fprintf(os.Stderr, "\nRun 'advent <command> -h' to see the flags of each command.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "advent: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	// Asking for the usage of a command is not an error, and the flag package already reported its own errors.
+	err := cmd(os.Args[2:])
+	var flagErr flagError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.As(err, &flagErr):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "advent %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
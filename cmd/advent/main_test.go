@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Bool("all", false, "")
+
+	var flagErr flagError
+	if err := parseFlags(flags, []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("parseFlags(-h) error = %v, want the help to be requested", err)
+	}
+	if err := parseFlags(flags, []string{"-bogus"}); !errors.As(err, &flagErr) {
+		t.Errorf("parseFlags(-bogus) error = %v, want a flagError", err)
+	}
+	if err := parseFlags(flags, []string{"-all"}); err != nil {
+		t.Errorf("parseFlags(-all) error = %v", err)
+	}
+}
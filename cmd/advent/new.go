@@ -0,0 +1,190 @@
+package main
+
+import (
+	"advent_2021/solver"
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// defaultTemplates are used to scaffold a new day, unless a template with the same name is found in the directory given
+// with -templates.
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// daysFile is the file importing every day into the runner, relative to the root of the repository.
+const daysFile = "cmd/advent/days.go"
+
+// scaffold describes a new day, and it is the data available to the templates.
+type scaffold struct {
+	Day     int
+	Dir     string
+	Package string
+}
+
+func newScaffold(day int) scaffold {
+	dir := fmt.Sprintf("%02d", day)
+	return scaffold{Day: day, Dir: dir, Package: "day" + dir}
+}
+
+// files maps every template to the file it generates, relative to the root of the repository.
+func (s scaffold) files() map[string]string {
+	return map[string]string{
+		"main.go.tmpl":      filepath.Join(s.Dir, "main.go"),
+		"main_test.go.tmpl": filepath.Join(s.Dir, "main_test.go"),
+		"example.txt.tmpl":  filepath.Join(s.Dir, "testdata", "example.txt"),
+	}
+}
+
+func newCommand(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	day := flags.Int("day", 0, "`day` to create, between 1 and 25")
+	templates := flags.String("templates", "", "`directory` with templates replacing the default ones")
+	root := flags.String("root", ".", "`directory` of the repository")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *day < 1 || *day > 25 {
+		return fmt.Errorf("the day must be between 1 and 25, got %d", *day)
+	}
+
+	s := newScaffold(*day)
+	if err := s.generate(*root, *templates); err != nil {
+		return err
+	}
+
+	fmt.Printf("Day %d: created %s, registered in %s.\n", s.Day, s.Dir, daysFile)
+	return nil
+}
+
+// generate writes the package of the new day, its example input and an empty input placeholder, and imports it into
+// the runner.  It refuses to touch a day that already exists, either as a directory or in the registry.
+func (s scaffold) generate(root string, templates string) error {
+	if _, ok := solver.New(s.Day); ok {
+		return fmt.Errorf("day %d is already registered", s.Day)
+	}
+	if _, err := os.Stat(filepath.Join(root, s.Dir)); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("day %d already exists in %s", s.Day, filepath.Join(root, s.Dir))
+	}
+
+	// We render everything before writing anything, so a broken template does not leave a half-created day behind.
+	rendered := make(map[string][]byte)
+	for name, path := range s.files() {
+		content, err := s.render(name, templates)
+		if err != nil {
+			return err
+		}
+		rendered[path] = content
+	}
+
+	days, err := s.register(filepath.Join(root, daysFile))
+	if err != nil {
+		return err
+	}
+
+	for path, content := range rendered {
+		if err := writeNewFile(filepath.Join(root, path), content); err != nil {
+			return err
+		}
+	}
+
+	input := filepath.Join(root, solver.InputPath(s.Day))
+	if _, err := os.Stat(input); errors.Is(err, os.ErrNotExist) {
+		if err := writeNewFile(input, nil); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(filepath.Join(root, daysFile), days, 0644)
+}
+
+// render executes a template, taken from the templates directory when it is there or from the default ones otherwise.
+// Go files are formatted, which also catches templates producing invalid code.
+func (s scaffold) render(name string, templates string) ([]byte, error) {
+	var (
+		text []byte
+		err  error
+	)
+	if templates != "" {
+		text, err = os.ReadFile(filepath.Join(templates, name))
+	}
+	if templates == "" || errors.Is(err, os.ErrNotExist) {
+		text, err = defaultTemplates.ReadFile("templates/" + name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+
+	var result bytes.Buffer
+	if err := tmpl.Execute(&result, s); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return result.Bytes(), nil
+	}
+	formatted, err := format.Source(result.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+
+	return formatted, nil
+}
+
+// register returns the contents of the days file with the import of the new day added to it.
+func (s scaffold) register(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	importPath := strconv.Quote("advent_2021/" + s.Dir)
+	if bytes.Contains(content, []byte(importPath)) {
+		return nil, fmt.Errorf("day %d is already imported in %s", s.Day, path)
+	}
+
+	end := bytes.LastIndexByte(content, ')')
+	if end < 0 {
+		return nil, fmt.Errorf("%s has no import block", path)
+	}
+
+	var result bytes.Buffer
+	result.Write(content[:end])
+	fmt.Fprintf(&result, "\t_ %s\n", importPath)
+	result.Write(content[end:])
+
+	return format.Source(result.Bytes())
+}
+
+// writeNewFile creates a file with its parent directories, failing if the file already exists.
+func writeNewFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScaffoldGenerate(t *testing.T) {
+	root := t.TempDir()
+	days := filepath.Join(root, daysFile)
+	if err := os.MkdirAll(filepath.Dir(days), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(days, []byte("package main\n\nimport (\n\t_ \"advent_2021/01\"\n)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newScaffold(25)
+	if err := s.generate(root, ""); err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	for _, path := range []string{"25/main.go", "25/main_test.go", "25/testdata/example.txt", "inputs/day25_exercise01.txt"} {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("%s was not created: %v", path, err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "25/main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "package day25\n") || !strings.Contains(string(content), "solver.Register(25,") {
+		t.Errorf("unexpected main.go:\n%s", content)
+	}
+
+	content, err = os.ReadFile(days)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "\t_ \"advent_2021/25\"\n") {
+		t.Errorf("day 25 is not imported:\n%s", content)
+	}
+
+	if err := s.generate(root, ""); err == nil {
+		t.Error("generate() overwrote an existing day")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"advent_2021/grid"
+	"advent_2021/picture"
+	"advent_2021/solver"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// pictureOptions are the flags about drawing what a part works on.
+type pictureOptions struct {
+	png     string
+	svg     string
+	palette string
+	scale   int
+}
+
+// requested reports whether any picture was asked for.
+func (o pictureOptions) requested() bool {
+	return o.png != "" || o.svg != ""
+}
+
+// check reports the options which would only fail once the pictures are drawn.
+func (o pictureOptions) check() error {
+	if o.scale < 1 {
+		return fmt.Errorf("invalid scale %d, it must be at least 1", o.scale)
+	}
+	if o.palette != "" {
+		if _, err := picture.ParsePalette(o.palette, 0, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportPictures draws what a part of a day works on into the requested files.  An empty input path reads the default
+// input of the day.
+func exportPictures(day, part int, input string, options pictureOptions) error {
+	s, ok := solver.New(day)
+	if !ok {
+		return fmt.Errorf("day %d is not registered", day)
+	}
+	drawer, ok := s.(picture.Drawer)
+	if !ok {
+		return fmt.Errorf("day %d cannot draw pictures", day)
+	}
+	if input == "" {
+		input = solver.InputPath(day)
+	}
+
+	if _, err := parseInput(s, input); err != nil {
+		return err
+	}
+	cells, palette, err := drawer.Draw(part)
+	if err != nil {
+		return err
+	}
+	if options.palette != "" {
+		min, max := picture.Range(cells)
+		if palette, err = picture.ParsePalette(options.palette, min, max); err != nil {
+			return err
+		}
+	}
+
+	exports := []struct {
+		path  string
+		write func(w io.Writer, g *grid.Grid[int], palette picture.Palette, scale int) error
+	}{
+		{options.png, picture.WritePNG},
+		{options.svg, picture.WriteSVG},
+	}
+	for _, export := range exports {
+		if export.path == "" {
+			continue
+		}
+
+		var content bytes.Buffer
+		if err := export.write(&content, cells, palette, options.scale); err != nil {
+			return err
+		}
+		if err := os.WriteFile(export.path, content.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
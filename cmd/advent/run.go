@@ -0,0 +1,215 @@
+package main
+
+import (
+	"advent_2021/solver"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// result holds the outcome of running a single part of a day.
+type result struct {
+	day, part int
+	input     string
+	inputHash string
+	answer    solver.Answer
+	duration  time.Duration
+	err       error
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	daySpec := flags.String("day", "", "day or days to run, e.g. `9`, `1-5` or `1,3,10-14`")
+	part := flags.Int("part", 0, "part to run, 1 or 2 (0 runs both)")
+	all := flags.Bool("all", false, "run every registered day")
+	input := flags.String("input", "", "read the input from this `file` instead of the default one, - reads stdin")
+	format := flags.String("format", "text", "output `format`: text, json or csv")
+	verbose := flags.Bool("v", false, "write the diagnostic output of the solvers to the standard error")
+	var pictures pictureOptions
+	flags.StringVar(&pictures.png, "png", "", "draw what the part works on as a PNG image in this `file`")
+	flags.StringVar(&pictures.svg, "svg", "", "draw what the part works on as an SVG image in this `file`")
+	flags.StringVar(&pictures.palette, "palette", "", "colour the pictures with this `palette`: heat, gray or mono "+
+		"(default the one chosen by the day)")
+	flags.IntVar(&pictures.scale, "scale", 1, "draw each cell of the pictures as a square of this `size`")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *verbose {
+		solver.Verbose = os.Stderr
+	}
+
+	days, err := selectDays(*daySpec, *all)
+	if err != nil {
+		return err
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid part %d, it must be 1 or 2", *part)
+	}
+	if *input != "" && len(days) != 1 {
+		return errors.New("-input can only be used with a single day")
+	}
+	if pictures.requested() && (len(days) != 1 || *part == 0) {
+		return errors.New("-png and -svg can only be used with a single day and part")
+	}
+	// Mistakes in the options are reported before solving anything, which can take a while.
+	write, err := resultWriter(*format)
+	if err != nil {
+		return err
+	}
+	if err := pictures.check(); err != nil {
+		return err
+	}
+
+	results := make([]result, 0)
+	for _, day := range days {
+		dayResults, err := runDay(day, selectParts(*part), *input)
+		if err != nil {
+			return err
+		}
+		results = append(results, dayResults...)
+	}
+
+	if err := write(os.Stdout, results); err != nil {
+		return err
+	}
+	if pictures.requested() {
+		if err := exportPictures(days[0], *part, *input, pictures); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			return errors.New("some parts failed")
+		}
+	}
+
+	return nil
+}
+
+// selectParts returns the parts chosen by the user, where 0 means both of them.
+func selectParts(part int) []int {
+	if part == 0 {
+		return []int{1, 2}
+	}
+
+	return []int{part}
+}
+
+// runDay parses the input of a day and solves the requested parts, timing how long each of them takes.  An empty path
+// reads the default input of the day.  In case the input cannot be parsed, the error is reported for every part.
+func runDay(day int, parts []int, path string) ([]result, error) {
+	s, ok := solver.New(day)
+	if !ok {
+		return nil, fmt.Errorf("day %d is not registered", day)
+	}
+	if path == "" {
+		path = solver.InputPath(day)
+	}
+
+	results := make([]result, 0, len(parts))
+	inputHash, parseErr := parseInput(s, path)
+
+	for _, part := range parts {
+		r := result{day: day, part: part, input: path, inputHash: inputHash, err: parseErr}
+		if parseErr == nil {
+			start := time.Now()
+			r.answer, r.err = solver.Solve(s, part)
+			r.duration = time.Since(start)
+		}
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// namedInput is an input read into memory, which keeps the name of the file it comes from, so errors parsing it point
+// at that file.
+type namedInput struct {
+	*bytes.Reader
+	name string
+}
+
+func (n namedInput) Name() string {
+	return n.name
+}
+
+// stdin keeps the standard input once it has been read, as it cannot be read again, and some commands read their
+// input twice, like run when it also draws pictures.
+var stdin struct {
+	read    bool
+	content []byte
+	err     error
+}
+
+// readInput reads a whole input file, where `-` reads the standard input, and returns it with the name of the file.
+func readInput(path string) (content []byte, name string, err error) {
+	if path == "-" {
+		if !stdin.read {
+			stdin.content, stdin.err = io.ReadAll(os.Stdin)
+			stdin.read = true
+		}
+		return stdin.content, os.Stdin.Name(), stdin.err
+	}
+
+	content, err = os.ReadFile(path)
+	return content, path, err
+}
+
+// parseInput feeds an input file to a solver, where `-` reads the standard input, and returns the SHA-256 hash of the
+// whole input, so results can be told apart by the input they come from.
+func parseInput(s solver.Solver, path string) (string, error) {
+	content, name, err := readInput(path)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), s.Parse(namedInput{Reader: bytes.NewReader(content), name: name})
+}
+
+// selectDays returns the days chosen by the user, either every registered day or the ones described by spec.
+func selectDays(spec string, all bool) ([]int, error) {
+	switch {
+	case all && spec != "":
+		return nil, errors.New("-all and -day are mutually exclusive")
+	case all:
+		return solver.Days(), nil
+	case spec == "":
+		return nil, errors.New("either -day or -all is required")
+	}
+
+	return parseDays(spec)
+}
+
+// parseDays expands a comma separated list of days and day ranges, like `1,3,10-14`, into the list of days it names.
+func parseDays(spec string) (result []int, err error) {
+	for _, field := range strings.Split(spec, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(field), "-", 2)
+
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid day %q", field)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid day range %q", field)
+			}
+		}
+
+		for day := first; day <= last; day++ {
+			result = append(result, day)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	day06 "advent_2021/06"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseInput(t *testing.T) {
+	dir := t.TempDir()
+
+	// Both inputs share their first line, which is all day 6 reads.
+	for _, content := range []string{"3,4,3,1,2\n", "3,4,3,1,2\n1,1\n"} {
+		path := filepath.Join(dir, "input.txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		hash, _ := parseInput(&day06.Puzzle{}, path)
+		want := sha256.Sum256([]byte(content))
+		if hash != hex.EncodeToString(want[:]) {
+			t.Errorf("parseInput() hash of %q = %s, want %x", content, hash, want)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.txt")
+	if err := os.WriteFile(bad, []byte("3,x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseInput(&day06.Puzzle{}, bad); err == nil || !strings.HasPrefix(err.Error(), bad+":1:") {
+		t.Errorf("parseInput() error = %v, want it located in %s", err, bad)
+	}
+}
+
+func TestParseStdinTwice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("3,4,3,1,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	original := os.Stdin
+	os.Stdin = file
+	defer func() { os.Stdin, stdin.read = original, false }()
+
+	// Like run does when it draws pictures, once to solve the parts and again to draw them.
+	first, err := parseInput(&day06.Puzzle{}, "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseInput(&day06.Puzzle{}, "-")
+	if err != nil || second != first {
+		t.Errorf("parseInput() of the standard input again = %s, %v, want %s", second, err, first)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"advent_2021/answers"
+	"advent_2021/aoc"
+	"advent_2021/solver"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+func submitCommand(args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ContinueOnError)
+	day := flags.Int("day", 0, "`day` to submit, between 1 and 25")
+	part := flags.Int("part", 0, "part to submit, 1 or 2")
+	answer := flags.String("answer", "", "answer to submit, instead of solving the default input of the day")
+	historyPath := flags.String("history", "submissions.txt", "`file` keeping every answer submitted")
+	answersPath := flags.String("answers", "answers.txt", "`file` where correct answers are recorded")
+	baseURL := flags.String("base-url", "", "`address` of the puzzle server (default $"+aoc.BaseURLEnv+" or "+aoc.DefaultBaseURL+")")
+	sessionFile := flags.String("session-file", "", "`file` with the session token, used when $"+aoc.SessionEnv+" is not set")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *day < 1 || *day > 25 {
+		return fmt.Errorf("the day must be between 1 and 25, got %d", *day)
+	}
+	if *part != 1 && *part != 2 {
+		return errors.New("-part must be 1 or 2")
+	}
+	if *answer == "" {
+		results, err := runDay(*day, []int{*part}, "")
+		if err != nil {
+			return err
+		}
+		if results[0].err != nil {
+			return results[0].err
+		}
+		*answer = results[0].answer.String()
+	}
+
+	history, err := aoc.LoadHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	if err := history.Check(*day, *part, *answer, time.Now()); err != nil {
+		return fmt.Errorf("refusing to submit: %w", err)
+	}
+
+	session, err := aoc.LoadSession(*sessionFile)
+	if err != nil {
+		return err
+	}
+	client := aoc.NewClient(*baseURL, session)
+
+	now := time.Now()
+	result, err := client.Submit(*day, *part, *answer)
+	if err != nil {
+		return err
+	}
+
+	history.Add(aoc.Submission{
+		Day: *day, Part: *part, Answer: *answer, Verdict: result.Verdict, Time: now, NotBefore: now.Add(result.Wait),
+	})
+	if err := history.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Day %d part %d, %s: %s.\n%s\n", *day, *part, *answer, result.Verdict, result.Message)
+
+	if result.Verdict == aoc.Correct {
+		return recordAnswer(*answersPath, *day, *part, *answer)
+	}
+
+	return nil
+}
+
+// recordAnswer stores an answer accepted by the server as the accepted answer for the default input of the day.
+func recordAnswer(path string, day, part int, answer string) error {
+	store, err := answers.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := store.Record(day, part, solver.InputPath(day), answer); err != nil {
+		return err
+	}
+
+	return store.Save()
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubmitDayRange(t *testing.T) {
+	// The day is checked before anything is read or sent.
+	for _, day := range []string{"0", "26", "-1"} {
+		err := submitCommand([]string{"-day", day, "-part", "1", "-answer", "7"})
+		if err == nil || !strings.Contains(err.Error(), "between 1 and 25") {
+			t.Errorf("submit -day %s error = %v, want the day out of range", day, err)
+		}
+	}
+}
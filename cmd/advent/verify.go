@@ -0,0 +1,123 @@
+package main
+
+import (
+	"advent_2021/answers"
+	"advent_2021/solver"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func verifyCommand(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	daySpec := flags.String("day", "", "day or days to verify, e.g. `9`, `1-5` or `1,3,10-14` (default all)")
+	all := flags.Bool("all", false, "verify every registered day")
+	part := flags.Int("part", 0, "part to verify, 1 or 2 (0 verifies both)")
+	inputs := flags.String("inputs", "all", "inputs to verify against: `real`, examples or all")
+	answersPath := flags.String("answers", "answers.txt", "`file` holding the accepted answers")
+	record := flags.Bool("record", false, "store the answers that are not known yet as accepted ones")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	days, err := selectDays(*daySpec, *all || *daySpec == "")
+	if err != nil {
+		return err
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid part %d, it must be 1 or 2", *part)
+	}
+	paths, err := selectInputs(*inputs)
+	if err != nil {
+		return err
+	}
+
+	store, err := answers.Load(*answersPath)
+	if err != nil {
+		return err
+	}
+
+	results := make([]result, 0)
+	for _, day := range days {
+		for _, path := range paths {
+			dayResults, err := runDay(day, selectParts(*part), path(day))
+			if err != nil {
+				return err
+			}
+			results = append(results, dayResults...)
+		}
+	}
+
+	failed, recorded := printVerification(results, store), 0
+	if *record {
+		for _, r := range results {
+			if r.err == nil && store.Check(r.day, r.part, r.input, r.answer.String()) == answers.Unknown {
+				if err := store.Record(r.day, r.part, r.input, r.answer.String()); err != nil {
+					return err
+				}
+				recorded++
+			}
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %d new answers in %s.\n", recorded, *answersPath)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d parts failed verification", failed)
+	}
+
+	return nil
+}
+
+// selectInputs returns the functions giving the input paths of a day to verify against.
+func selectInputs(inputs string) ([]func(int) string, error) {
+	switch inputs {
+	case "real":
+		return []func(int) string{solver.InputPath}, nil
+	case "examples":
+		return []func(int) string{solver.ExamplePath}, nil
+	case "all":
+		return []func(int) string{solver.ExamplePath, solver.InputPath}, nil
+	default:
+		return nil, errors.New("-inputs must be real, examples or all")
+	}
+}
+
+// printVerification writes a table comparing each result with its accepted answer, followed by a summary, and
+// returns the number of failures.  A part that could not be solved counts as a failure, unless it has no answer for
+// that input.
+func printVerification(results []result, store *answers.Store) (failed int) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DAY\tPART\tINPUT\tSTATUS\tANSWER\tEXPECTED\tTIME")
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		checked := store.Check(r.day, r.part, r.input, r.answer.String())
+		status, answer := checked.String(), r.answer.String()
+		if r.err != nil {
+			answer = r.err.Error()
+			if checked != answers.Skipped {
+				status = "error"
+			}
+		}
+		expected, ok := store.Lookup(r.day, r.part, r.input)
+		if !ok {
+			expected = "-"
+		}
+
+		counts[status]++
+		fmt.Fprintf(writer, "%d\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			r.day, r.part, r.input, status, answer, expected, r.duration.Round(time.Microsecond))
+	}
+	writer.Flush()
+
+	fmt.Printf("\n%d passed, %d failed, %d unknown, %d skipped, %d errors.\n",
+		counts["pass"], counts["fail"], counts["unknown"], counts["skipped"], counts["error"])
+
+	return counts["fail"] + counts["error"]
+}
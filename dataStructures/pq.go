@@ -2,41 +2,131 @@ package dataStructures
 
 import "container/heap"
 
-type Item struct {
-	Value    rune
-	Priority int
-	index    int
+// Item is an element of a PriorityQueue.  It is returned when pushing a value, and works as a handle to update or
+// remove that value later on.  Its Value must only be changed through PriorityQueue.Update.
+type Item[T any] struct {
+	Value T
+	index int
+	order uint64
 }
 
-type RunePriorityQueue []*Item
+// PriorityQueue is a heap of values sorted by a configurable ordering, where the first value is the one which goes
+// before every other.  Values which are equal for the ordering come out in the same order they were pushed.
+type PriorityQueue[T any] struct {
+	items  items[T]
+	pushed uint64
+}
+
+// NewPriorityQueue returns an empty PriorityQueue, where less reports whether a value goes before another one.
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{items: items[T]{less: less}}
+}
+
+// NewMinQueue returns an empty PriorityQueue where the smallest priority goes first.
+func NewMinQueue[T any](priority func(value T) int) *PriorityQueue[T] {
+	return NewPriorityQueue(func(a, b T) bool { return priority(a) < priority(b) })
+}
 
-func (rpq RunePriorityQueue) Len() int           { return len(rpq) }
-func (rpq RunePriorityQueue) Less(i, j int) bool { return rpq[i].Priority > rpq[j].Priority }
-func (rpq RunePriorityQueue) Swap(i, j int) {
-	rpq[i], rpq[j] = rpq[j], rpq[i]
-	rpq[i].index = i
-	rpq[j].index = j
+// NewMaxQueue returns an empty PriorityQueue where the largest priority goes first.
+func NewMaxQueue[T any](priority func(value T) int) *PriorityQueue[T] {
+	return NewPriorityQueue(func(a, b T) bool { return priority(a) > priority(b) })
 }
 
-func (rpq *RunePriorityQueue) Push(x interface{}) {
-	n := len(*rpq)
-	item := x.(*Item)
-	item.index = n
-	*rpq = append(*rpq, item)
+// Len returns the number of values in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items.list)
 }
 
-func (rpq *RunePriorityQueue) Pop() interface{} {
-	old := *rpq
-	n := len(old)
-	item := old[n-1]
-	old[n-1] = nil  // avoid memory leak
-	item.index = -1 // for safety
-	*rpq = old[0 : n-1]
+// Push adds a value to the queue, returning its handle.
+func (pq *PriorityQueue[T]) Push(value T) *Item[T] {
+	item := &Item[T]{Value: value, order: pq.pushed}
+	pq.pushed++
+	heap.Push(&pq.items, item)
+
 	return item
 }
 
-func (rpq *RunePriorityQueue) update(item *Item, value rune, priority int) {
+// Peek returns the first value of the queue without removing it, or false if the queue is empty.
+func (pq *PriorityQueue[T]) Peek() (value T, ok bool) {
+	if pq.Len() == 0 {
+		return value, false
+	}
+
+	return pq.items.list[0].Value, true
+}
+
+// Pop removes and returns the first value of the queue, or false if the queue is empty.
+func (pq *PriorityQueue[T]) Pop() (value T, ok bool) {
+	if pq.Len() == 0 {
+		return value, false
+	}
+
+	return heap.Pop(&pq.items).(*Item[T]).Value, true
+}
+
+// Update changes the value of an item still in the queue, moving it to its new place, like a decrease-key.  The item
+// keeps its original place among the values equal to it.
+func (pq *PriorityQueue[T]) Update(item *Item[T], value T) bool {
+	if !pq.contains(item) {
+		return false
+	}
+
 	item.Value = value
-	item.Priority = priority
-	heap.Fix(rpq, item.index)
+	heap.Fix(&pq.items, item.index)
+
+	return true
+}
+
+// Remove takes an item out of the queue, returning false if it was not in it.
+func (pq *PriorityQueue[T]) Remove(item *Item[T]) bool {
+	if !pq.contains(item) {
+		return false
+	}
+
+	heap.Remove(&pq.items, item.index)
+	return true
+}
+
+// contains reports whether an item belongs to this queue.
+func (pq *PriorityQueue[T]) contains(item *Item[T]) bool {
+	return item.index >= 0 && item.index < pq.Len() && pq.items.list[item.index] == item
+}
+
+// items implements heap.Interface, breaking the ties of the ordering by the push order.
+type items[T any] struct {
+	list []*Item[T]
+	less func(a, b T) bool
+}
+
+func (it items[T]) Len() int { return len(it.list) }
+func (it items[T]) Less(i, j int) bool {
+	a, b := it.list[i], it.list[j]
+	switch {
+	case it.less(a.Value, b.Value):
+		return true
+	case it.less(b.Value, a.Value):
+		return false
+	default:
+		return a.order < b.order
+	}
+}
+func (it items[T]) Swap(i, j int) {
+	it.list[i], it.list[j] = it.list[j], it.list[i]
+	it.list[i].index = i
+	it.list[j].index = j
+}
+
+func (it *items[T]) Push(x interface{}) {
+	item := x.(*Item[T])
+	item.index = len(it.list)
+	it.list = append(it.list, item)
+}
+
+func (it *items[T]) Pop() interface{} {
+	n := len(it.list)
+	item := it.list[n-1]
+	it.list[n-1] = nil // avoid memory leak
+	item.index = -1    // for safety
+	it.list = it.list[:n-1]
+	return item
 }
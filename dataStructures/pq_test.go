@@ -0,0 +1,121 @@
+package dataStructures
+
+import (
+	"reflect"
+	"testing"
+)
+
+type task struct {
+	name     string
+	priority int
+}
+
+func byPriority(t task) int {
+	return t.priority
+}
+
+// drain pops every value left in a queue, returning their names in order.
+func drain(pq *PriorityQueue[task]) (result []string) {
+	for pq.Len() > 0 {
+		value, _ := pq.Pop()
+		result = append(result, value.name)
+	}
+
+	return result
+}
+
+func TestPriorityQueueOrder(t *testing.T) {
+	tasks := []task{{"a", 3}, {"b", 1}, {"c", 2}, {"d", 1}, {"e", 3}}
+
+	tests := []struct {
+		name string
+		pq   *PriorityQueue[task]
+		want []string
+	}{
+		// Ties come out in the same order they were pushed.
+		{"min", NewMinQueue(byPriority), []string{"b", "d", "c", "a", "e"}},
+		{"max", NewMaxQueue(byPriority), []string{"a", "e", "c", "b", "d"}},
+		{"custom", NewPriorityQueue(func(a, b task) bool { return a.name > b.name }), []string{"e", "d", "c", "b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, value := range tasks {
+				tt.pq.Push(value)
+			}
+
+			if got := drain(tt.pq); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("popped %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriorityQueueEmpty(t *testing.T) {
+	pq := NewMinQueue(byPriority)
+
+	if _, ok := pq.Peek(); ok {
+		t.Error("Peek() on an empty queue returned a value")
+	}
+	if _, ok := pq.Pop(); ok {
+		t.Error("Pop() on an empty queue returned a value")
+	}
+}
+
+func TestPriorityQueuePeek(t *testing.T) {
+	pq := NewMinQueue(byPriority)
+	pq.Push(task{"a", 2})
+	pq.Push(task{"b", 1})
+
+	if value, ok := pq.Peek(); !ok || value.name != "b" {
+		t.Errorf("Peek() = %v, %v, want b", value, ok)
+	}
+	if pq.Len() != 2 {
+		t.Errorf("Len() = %d after Peek(), want 2", pq.Len())
+	}
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	pq := NewMinQueue(byPriority)
+	pq.Push(task{"a", 1})
+	b := pq.Push(task{"b", 5})
+	pq.Push(task{"c", 3})
+
+	// Decrease key, then increase key again.
+	if !pq.Update(b, task{"b", 0}) {
+		t.Fatal("Update() did not find the item")
+	}
+	if value, _ := pq.Peek(); value.name != "b" {
+		t.Errorf("after decreasing b the first value is %s, want b", value.name)
+	}
+	pq.Update(b, task{"b", 2})
+
+	if got, want := drain(pq), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+
+	if pq.Update(b, task{"b", 0}) {
+		t.Error("Update() changed an item already popped")
+	}
+}
+
+func TestPriorityQueueRemove(t *testing.T) {
+	pq := NewMinQueue(byPriority)
+	a := pq.Push(task{"a", 1})
+	pq.Push(task{"b", 2})
+	c := pq.Push(task{"c", 3})
+
+	if !pq.Remove(a) || !pq.Remove(c) {
+		t.Fatal("Remove() did not find the items")
+	}
+	if pq.Remove(a) {
+		t.Error("Remove() removed the same item twice")
+	}
+	if pq.Remove(NewMinQueue(byPriority).Push(task{"d", 0})) {
+		t.Error("Remove() removed an item of another queue")
+	}
+
+	if got, want := drain(pq), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+}
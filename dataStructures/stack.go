@@ -0,0 +1,40 @@
+package dataStructures
+
+// Stack is a last in, first out collection of values.  The zero value is an empty stack ready to use.
+type Stack[T any] struct {
+	values []T
+}
+
+// Len returns the number of values in the stack.
+func (s *Stack[T]) Len() int {
+	return len(s.values)
+}
+
+// Push adds a value on top of the stack.
+func (s *Stack[T]) Push(value T) {
+	s.values = append(s.values, value)
+}
+
+// Peek returns the value on top of the stack without removing it, or false if the stack is empty.
+func (s *Stack[T]) Peek() (value T, ok bool) {
+	if len(s.values) == 0 {
+		return value, false
+	}
+
+	return s.values[len(s.values)-1], true
+}
+
+// Pop removes and returns the value on top of the stack, or false if the stack is empty.
+func (s *Stack[T]) Pop() (value T, ok bool) {
+	if len(s.values) == 0 {
+		return value, false
+	}
+
+	n := len(s.values) - 1
+	value = s.values[n]
+	var zero T
+	s.values[n] = zero // avoid memory leak
+	s.values = s.values[:n]
+
+	return value, true
+}
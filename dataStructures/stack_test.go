@@ -0,0 +1,30 @@
+package dataStructures
+
+import "testing"
+
+func TestStack(t *testing.T) {
+	var s Stack[string]
+
+	if _, ok := s.Pop(); ok {
+		t.Error("Pop() on an empty stack returned a value")
+	}
+	if _, ok := s.Peek(); ok {
+		t.Error("Peek() on an empty stack returned a value")
+	}
+
+	s.Push("a")
+	s.Push("b")
+	if value, ok := s.Peek(); !ok || value != "b" || s.Len() != 2 {
+		t.Errorf("Peek() = %q, %v with %d values, want b with 2 values", value, ok, s.Len())
+	}
+
+	s.Push("c")
+	for _, want := range []string{"c", "b", "a"} {
+		if value, ok := s.Pop(); !ok || value != want {
+			t.Errorf("Pop() = %q, %v, want %q", value, ok, want)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d after popping everything, want 0", s.Len())
+	}
+}
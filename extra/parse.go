@@ -0,0 +1,236 @@
+package extra
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseError reports a malformed piece of input, pointing at the source, line and column where it was found.  Lines and
+// columns start at 1, and columns are counted in bytes.
+type ParseError struct {
+	Source string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %v", e.Source, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Line is a line of input, or a section of it, which remembers where it comes from so any error parsing it can be
+// precisely located.
+type Line struct {
+	Source string
+	Number int
+	Column int
+	Text   string
+}
+
+// Errorf returns a ParseError located at the given byte offset of the line text.
+func (l Line) Errorf(offset int, format string, a ...interface{}) error {
+	return &ParseError{Source: l.Source, Line: l.Number, Column: l.Column + offset, Err: fmt.Errorf(format, a...)}
+}
+
+// slice returns the section of the line between two byte offsets of its text.
+func (l Line) slice(start, end int) Line {
+	return Line{Source: l.Source, Number: l.Number, Column: l.Column + start, Text: l.Text[start:end]}
+}
+
+// Split slices the line into all the sections separated by sep.
+func (l Line) Split(sep string) (result []Line) {
+	start := 0
+	for {
+		index := strings.Index(l.Text[start:], sep)
+		if index < 0 {
+			return append(result, l.slice(start, len(l.Text)))
+		}
+
+		result = append(result, l.slice(start, start+index))
+		start += index + len(sep)
+	}
+}
+
+// Fields slices the line around each run of white space, like strings.Fields.
+func (l Line) Fields() (result []Line) {
+	start := -1
+	for i, r := range l.Text {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			result = append(result, l.slice(start, i))
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		result = append(result, l.slice(start, len(l.Text)))
+	}
+
+	return result
+}
+
+// Cut slices the line around the first instance of sep, failing if sep cannot be found.
+func (l Line) Cut(sep string) (before Line, after Line, err error) {
+	index := strings.Index(l.Text, sep)
+	if index < 0 {
+		return before, after, l.Errorf(0, "expected %q in %q", sep, l.Text)
+	}
+
+	return l.slice(0, index), l.slice(index+len(sep), len(l.Text)), nil
+}
+
+// Int parses the whole line as a decimal integer.
+func (l Line) Int() (int, error) {
+	value, err := strconv.Atoi(l.Text)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return 0, l.Errorf(0, "invalid number %q: %w", l.Text, err)
+	}
+
+	return value, nil
+}
+
+// IntList parses a list of integers separated by sep.  An empty separator splits the line around white space instead.
+func (l Line) IntList(sep string) ([]int, error) {
+	var fields []Line
+	if sep == "" {
+		fields = l.Fields()
+	} else {
+		fields = l.Split(sep)
+	}
+
+	result := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := field.Int()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = value
+	}
+
+	return result, nil
+}
+
+// Digits parses each character of the line as a single decimal digit.
+func (l Line) Digits() ([]int, error) {
+	result := make([]int, len(l.Text))
+
+	for i := 0; i < len(l.Text); i++ {
+		if l.Text[i] < '0' || l.Text[i] > '9' {
+			return nil, l.Errorf(i, "invalid digit %q", l.Text[i])
+		}
+		result[i] = int(l.Text[i] - '0')
+	}
+
+	return result, nil
+}
+
+// Rule parses a line with the shape `a -> b`, returning both sides of the arrow.
+func (l Line) Rule() (from Line, to Line, err error) {
+	return l.Cut(" -> ")
+}
+
+// Pair parses a line with the shape `x,y` into its two integers.
+func (l Line) Pair() (x int, y int, err error) {
+	left, right, err := l.Cut(",")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if x, err = left.Int(); err != nil {
+		return 0, 0, err
+	}
+	if y, err = right.Int(); err != nil {
+		return 0, 0, err
+	}
+
+	return x, y, nil
+}
+
+// Scanner reads an input line by line, keeping count of the lines so they can be located on errors.
+type Scanner struct {
+	scanner *bufio.Scanner
+	source  string
+	line    int
+}
+
+// NewScanner returns a Scanner reading from input.  The source is named after the input when it has a name, as files
+// do, or just `input` otherwise.
+func NewScanner(input io.Reader) *Scanner {
+	source := "input"
+	if named, ok := input.(interface{ Name() string }); ok {
+		source = named.Name()
+	}
+
+	return &Scanner{scanner: bufio.NewScanner(input), source: source}
+}
+
+// Scan advances to the next line, returning false when there are no more lines or reading failed.
+func (s *Scanner) Scan() bool {
+	if !s.scanner.Scan() {
+		return false
+	}
+	s.line++
+
+	return true
+}
+
+// Line returns the current line.
+func (s *Scanner) Line() Line {
+	return Line{Source: s.source, Number: s.line, Column: 1, Text: s.scanner.Text()}
+}
+
+// Text returns the text of the current line.
+func (s *Scanner) Text() string {
+	return s.scanner.Text()
+}
+
+// Err returns the first non-EOF error found while reading.
+func (s *Scanner) Err() error {
+	return s.scanner.Err()
+}
+
+// Errorf returns a ParseError located at the end of the input read so far, for errors about missing content.
+func (s *Scanner) Errorf(format string, a ...interface{}) error {
+	return &ParseError{Source: s.source, Line: s.line, Column: 1, Err: fmt.Errorf(format, a...)}
+}
+
+// ReadDigitGrid reads the rest of the input as a rectangular grid of single digits, where each line is a row.
+func ReadDigitGrid(s *Scanner) ([][]int, error) {
+	result := make([][]int, 0)
+
+	for s.Scan() {
+		line := s.Line()
+		row, err := line.Digits()
+		if err != nil {
+			return nil, err
+		}
+		if len(result) > 0 && len(row) != len(result[0]) {
+			return nil, line.Errorf(0, "row has %d digits, expected %d", len(row), len(result[0]))
+		}
+
+		result = append(result, row)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, s.Errorf("the grid is empty")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package extra
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func line(text string) Line {
+	return Line{Source: "test.txt", Number: 3, Column: 1, Text: text}
+}
+
+func TestIntList(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		sep     string
+		want    []int
+		wantErr string
+	}{
+		{"commas", "3,4,-3", ",", []int{3, 4, -3}, ""},
+		{"white space", " 8  2 23", "", []int{8, 2, 23}, ""},
+		{"bad number", "3,x,4", ",", nil, `test.txt:3:3: invalid number "x": invalid syntax`},
+		{"empty field", "3,,4", ",", nil, `test.txt:3:3: invalid number "": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := line(tt.text).IntList(tt.sep)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleAndPair(t *testing.T) {
+	from, to, err := line("0,9 -> 5,9").Rule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from.Text != "0,9" || to.Text != "5,9" || to.Column != 8 {
+		t.Errorf("Rule() = %+v, %+v", from, to)
+	}
+
+	x, y, err := to.Pair()
+	if err != nil || x != 5 || y != 9 {
+		t.Errorf("Pair() = %d, %d, %v, want 5, 9", x, y, err)
+	}
+
+	_, to, err = line("0,9 -> 5;9").Rule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = to.Pair(); err == nil || err.Error() != `test.txt:3:8: expected "," in "5;9"` {
+		t.Errorf("Pair() error = %v", err)
+	}
+}
+
+func TestErrorsUnwrap(t *testing.T) {
+	_, err := line("99999999999999999999").Int()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 3 || parseErr.Column != 1 {
+		t.Fatalf("error = %#v, want a ParseError at 3:1", err)
+	}
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("error = %v, want it to wrap strconv.ErrRange", err)
+	}
+}
+
+func TestReadDigitGrid(t *testing.T) {
+	grid, err := ReadDigitGrid(NewScanner(strings.NewReader("219\n398\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int{{2, 1, 9}, {3, 9, 8}}; !reflect.DeepEqual(grid, want) {
+		t.Errorf("got %v, want %v", grid, want)
+	}
+
+	tests := map[string]string{
+		"219\n3a8\n": "input:2:2: invalid digit 'a'",
+		"219\n39\n":  "input:2:1: row has 2 digits, expected 3",
+		"":           "input:0:1: the grid is empty",
+	}
+	for input, want := range tests {
+		if _, err := ReadDigitGrid(NewScanner(strings.NewReader(input))); err == nil || err.Error() != want {
+			t.Errorf("ReadDigitGrid(%q) error = %v, want %s", input, err, want)
+		}
+	}
+}
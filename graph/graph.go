@@ -0,0 +1,100 @@
+// Package graph provides a graph of named nodes, with attributes attached to them, and the traversals shared by the
+// puzzles working on such graphs.
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeID identifies a node of a graph.  Nodes are numbered from 0 in the order they are added, so IDs can index
+// slices.
+type NodeID int
+
+// Graph is a directed graph whose nodes are interned by name and carry an attribute of type A.  Undirected graphs are
+// built adding the arcs in both directions with AddEdge.
+type Graph[A any] struct {
+	ids   map[string]NodeID
+	names []string
+	attrs []A
+	arcs  [][]NodeID
+}
+
+// New returns an empty graph.
+func New[A any]() *Graph[A] {
+	return &Graph[A]{ids: make(map[string]NodeID)}
+}
+
+// Len returns the number of nodes of the graph.
+func (g *Graph[A]) Len() int {
+	return len(g.names)
+}
+
+// Intern returns the ID of the node with the given name, adding it with the attribute returned by attr if the graph did
+// not have it yet.
+func (g *Graph[A]) Intern(name string, attr func(name string) A) NodeID {
+	if id, ok := g.ids[name]; ok {
+		return id
+	}
+
+	id := NodeID(len(g.names))
+	g.ids[name] = id
+	g.names = append(g.names, name)
+	g.attrs = append(g.attrs, attr(name))
+	g.arcs = append(g.arcs, nil)
+
+	return id
+}
+
+// ID returns the ID of the node with the given name, or false if there is no such node.
+func (g *Graph[A]) ID(name string) (NodeID, bool) {
+	id, ok := g.ids[name]
+	return id, ok
+}
+
+// Name returns the name of a node.
+func (g *Graph[A]) Name(id NodeID) string {
+	return g.names[id]
+}
+
+// Attr returns the attribute of a node.
+func (g *Graph[A]) Attr(id NodeID) A {
+	return g.attrs[id]
+}
+
+// AddArc connects a node to another one, in that direction only.  Adding an arc twice has no effect.
+func (g *Graph[A]) AddArc(from, to NodeID) {
+	for _, neighbour := range g.arcs[from] {
+		if neighbour == to {
+			return
+		}
+	}
+
+	g.arcs[from] = append(g.arcs[from], to)
+}
+
+// AddEdge connects two nodes in both directions.
+func (g *Graph[A]) AddEdge(a, b NodeID) {
+	g.AddArc(a, b)
+	g.AddArc(b, a)
+}
+
+// Neighbours returns the nodes reached by the arcs leaving a node, in the order they were added.  The result must not
+// be modified.
+func (g *Graph[A]) Neighbours(id NodeID) []NodeID {
+	return g.arcs[id]
+}
+
+// String lists every node with the names of its neighbours, one node per line.
+func (g *Graph[A]) String() string {
+	var result strings.Builder
+	for id, name := range g.names {
+		neighbours := make([]string, len(g.arcs[id]))
+		for i, neighbour := range g.arcs[id] {
+			neighbours[i] = g.names[neighbour]
+		}
+		fmt.Fprintf(&result, "%s: %s\n", name, strings.Join(neighbours, ", "))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sample builds the undirected graph
+//
+//	a - b - d
+//	|   |
+//	c --+
+func sample() *Graph[int] {
+	g := New[int]()
+	length := func(name string) int { return len(name) }
+
+	for _, edge := range []string{"a-b", "a-c", "b-c", "b-d", "a-b"} {
+		left, right, _ := strings.Cut(edge, "-")
+		g.AddEdge(g.Intern(left, length), g.Intern(right, length))
+	}
+
+	return g
+}
+
+// names returns the names of a list of nodes.
+func names(g *Graph[int], ids []NodeID) string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = g.Name(id)
+	}
+
+	return strings.Join(result, ",")
+}
+
+func TestIntern(t *testing.T) {
+	g := sample()
+
+	if g.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", g.Len())
+	}
+	if id, ok := g.ID("c"); !ok || g.Name(id) != "c" || g.Attr(id) != 1 {
+		t.Errorf("ID(c) = %d, %v, named %s", id, ok, g.Name(id))
+	}
+	if _, ok := g.ID("z"); ok {
+		t.Error("ID() found a node which was never added")
+	}
+
+	// The repeated edge is only added once.
+	a, _ := g.ID("a")
+	if got := names(g, g.Neighbours(a)); got != "b,c" {
+		t.Errorf("Neighbours(a) = %s, want b,c", got)
+	}
+
+	if want := "a: b, c\nb: a, c, d\nc: a, b\nd: b\n"; g.String() != want {
+		t.Errorf("String() =\n%swant\n%s", g.String(), want)
+	}
+}
+
+func TestTraversals(t *testing.T) {
+	g := sample()
+	a, _ := g.ID("a")
+
+	var dfs []NodeID
+	g.DFS(a, func(id NodeID) bool {
+		dfs = append(dfs, id)
+		return true
+	})
+	if got := names(g, dfs); got != "a,b,c,d" {
+		t.Errorf("DFS() visited %s, want a,b,c,d", got)
+	}
+
+	distances := make(map[string]int)
+	g.BFS(a, func(id NodeID, distance int) bool {
+		distances[g.Name(id)] = distance
+		return true
+	})
+	if want := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2}; !reflect.DeepEqual(distances, want) {
+		t.Errorf("BFS() distances = %v, want %v", distances, want)
+	}
+
+	visited := 0
+	g.DFS(a, func(id NodeID) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Errorf("DFS() visited %d nodes after being stopped at the second one", visited)
+	}
+}
+
+func TestAllPaths(t *testing.T) {
+	g := sample()
+	a, _ := g.ID("a")
+	d, _ := g.ID("d")
+	once := func(NodeID) (int, int) { return 1, 2 }
+
+	var paths []string
+	count := g.AllPaths(a, d, NewQuota(g.Len(), once, 0), func(path []NodeID) bool {
+		paths = append(paths, names(g, path))
+		return true
+	})
+	if want := []string{"a,b,d", "a,c,b,d"}; count != 2 || !reflect.DeepEqual(paths, want) {
+		t.Errorf("AllPaths() = %d, %v, want 2, %v", count, paths, want)
+	}
+
+	// A spare visit lets a single node be visited twice, which adds a,b,c,b,d and a,c,a,b,d.
+	if count := g.AllPaths(a, d, NewQuota(g.Len(), once, 1), nil); count != 4 {
+		t.Errorf("AllPaths() with a spare visit = %d, want 4", count)
+	}
+
+	if count := g.AllPaths(a, d, NewQuota(g.Len(), once, 0), func([]NodeID) bool { return false }); count != 1 {
+		t.Errorf("AllPaths() stopped at the first path = %d, want 1", count)
+	}
+}
+
+func TestQuotaExtended(t *testing.T) {
+	// A hub with two leaves, between the start and the end.
+	g := New[int]()
+	zero := func(string) int { return 0 }
+	hub := g.Intern("hub", zero)
+	for _, name := range []string{"start", "left", "right", "end"} {
+		g.AddEdge(hub, g.Intern(name, zero))
+	}
+	start, _ := g.ID("start")
+	end, _ := g.ID("end")
+
+	tests := []struct {
+		extended int
+		spares   int
+		want     int
+	}{
+		{1, 1, 1},
+		{2, 0, 1},
+		// start,hub,left,hub,end and start,hub,right,hub,end.
+		{2, 1, 3},
+		// start,hub,left,hub,right,hub,end and start,hub,right,hub,left,hub,end.
+		{3, 1, 5},
+	}
+
+	for _, tt := range tests {
+		limit := func(id NodeID) (int, int) {
+			if id == hub {
+				return 1, tt.extended
+			}
+			return 1, 1
+		}
+
+		if count := g.AllPaths(start, end, NewQuota(g.Len(), limit, tt.spares), nil); count != tt.want {
+			t.Errorf("AllPaths() extending the hub to %d visits with %d spares = %d, want %d", tt.extended, tt.spares,
+				count, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package graph
+
+// DFS visits every node reachable from start once, depth first, in the order the arcs were added.  It stops as soon as
+// visit returns false.
+func (g *Graph[A]) DFS(start NodeID, visit func(id NodeID) bool) {
+	seen := make([]bool, g.Len())
+	pending := []NodeID{start}
+
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if !visit(id) {
+			return
+		}
+
+		// We push the neighbours backwards, so the first one added is the first one visited.
+		for i := len(g.arcs[id]) - 1; i >= 0; i-- {
+			if !seen[g.arcs[id][i]] {
+				pending = append(pending, g.arcs[id][i])
+			}
+		}
+	}
+}
+
+// BFS visits every node reachable from start once, breadth first, together with its distance in arcs from start.  It
+// stops as soon as visit returns false.
+func (g *Graph[A]) BFS(start NodeID, visit func(id NodeID, distance int) bool) {
+	distances := make([]int, g.Len())
+	for i := range distances {
+		distances[i] = -1
+	}
+	distances[start] = 0
+	queue := []NodeID{start}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if !visit(id, distances[id]) {
+			return
+		}
+
+		for _, neighbour := range g.arcs[id] {
+			if distances[neighbour] < 0 {
+				distances[neighbour] = distances[id] + 1
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+}
+
+// Rule decides which nodes a path may go through.  Enter reports whether the path may go on to a node, recording the
+// visit when it may, and Leave undoes the last visit to a node when the path steps back.
+type Rule interface {
+	Enter(id NodeID) bool
+	Leave(id NodeID)
+}
+
+// Quota is a Rule giving each node a maximum number of visits.  On top of them, a number of spares can be shared among
+// the nodes, each of them letting a path go over the limit of a node up to an extended one.
+type Quota struct {
+	limit  func(id NodeID) (visits int, extended int)
+	visits []int
+	spares int
+}
+
+// NewQuota returns a Quota for a graph with nodes, where limit returns the maximum number of visits of each node,
+// negative for no limit, and the limit a spare extends it to, which is ignored unless it is larger.
+func NewQuota(nodes int, limit func(id NodeID) (visits int, extended int), spares int) *Quota {
+	return &Quota{limit: limit, visits: make([]int, nodes), spares: spares}
+}
+
+func (q *Quota) Enter(id NodeID) bool {
+	limit, extended := q.limit(id)
+
+	switch visits := q.visits[id]; {
+	case limit < 0 || visits < limit:
+	case visits >= extended:
+		return false
+	case visits > limit:
+		// The node already took a spare.
+	case q.spares > 0:
+		q.spares--
+	default:
+		return false
+	}
+
+	q.visits[id]++
+	return true
+}
+
+func (q *Quota) Leave(id NodeID) {
+	q.visits[id]--
+
+	if limit, _ := q.limit(id); limit >= 0 && q.visits[id] == limit {
+		q.spares++
+	}
+}
+
+// AllPaths goes through every path from one node to another allowed by a rule, calling visit with each of them, and
+// returns how many there are.  A path ends as soon as it reaches its destination.  The path given to visit is reused
+// afterwards, so it must be copied to keep it, and returning false stops the search.  The rule must not allow endless
+// paths, like one going back and forth between two nodes without limit.
+func (g *Graph[A]) AllPaths(from, to NodeID, rule Rule, visit func(path []NodeID) bool) (count int) {
+	if !rule.Enter(from) {
+		return 0
+	}
+
+	path := []NodeID{from}
+	var walk func() bool
+	walk = func() bool {
+		current := path[len(path)-1]
+		if current == to {
+			count++
+			return visit == nil || visit(path)
+		}
+
+		for _, next := range g.arcs[current] {
+			if !rule.Enter(next) {
+				continue
+			}
+
+			path = append(path, next)
+			goOn := walk()
+			path = path[:len(path)-1]
+			rule.Leave(next)
+
+			if !goOn {
+				return false
+			}
+		}
+
+		return true
+	}
+	walk()
+	rule.Leave(from)
+
+	return count
+}
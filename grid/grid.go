@@ -0,0 +1,308 @@
+// Package grid provides a generic rectangular grid, as found in many puzzles: heightmaps, boards of octopuses, sheets of
+// paper with dots...
+package grid
+
+import (
+	"advent_2021/extra"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Point is a position in a grid, where X is the column and Y the row, both starting at 0 on the top left corner.
+type Point struct {
+	X, Y int
+}
+
+// Add returns the point moved by the given offset.
+func (p Point) Add(offset Point) Point {
+	return Point{X: p.X + offset.X, Y: p.Y + offset.Y}
+}
+
+var (
+	// offsets4 are the offsets of the neighbours sharing a side with a point.
+	offsets4 = []Point{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	// offsets8 are the offsets of all the neighbours around a point, diagonals included.
+	offsets8 = []Point{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+)
+
+// Grid is a rectangular grid of cells, stored row by row.
+type Grid[T any] struct {
+	width, height int
+	cells         []T
+}
+
+// New returns a grid of the given size, with every cell set to the zero value.
+func New[T any](width, height int) *Grid[T] {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("invalid grid size %dx%d", width, height))
+	}
+
+	return &Grid[T]{width: width, height: height, cells: make([]T, width*height)}
+}
+
+// FromRows returns a grid holding a copy of the given rows, failing if they do not have all the same length.
+func FromRows[T any](rows [][]T) (*Grid[T], error) {
+	if len(rows) == 0 {
+		return New[T](0, 0), nil
+	}
+
+	g := New[T](len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != g.width {
+			return nil, fmt.Errorf("row %d has %d cells, expected %d", y, len(row), g.width)
+		}
+		copy(g.cells[y*g.width:], row)
+	}
+
+	return g, nil
+}
+
+// ReadDigits reads the rest of the input as a grid of single digits, where each line is a row.
+func ReadDigits(s *extra.Scanner) (*Grid[int], error) {
+	rows, err := extra.ReadDigitGrid(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromRows(rows)
+}
+
+// ReadRunes reads the rest of the input as a grid of characters, where each line is a row.
+func ReadRunes(s *extra.Scanner) (*Grid[rune], error) {
+	var rows [][]rune
+
+	for s.Scan() {
+		line := s.Line()
+		row := []rune(line.Text)
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, line.Errorf(0, "row has %d characters, expected %d", len(row), len(rows[0]))
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, s.Errorf("the grid is empty")
+	}
+
+	return FromRows(rows)
+}
+
+// Width returns the number of columns of the grid.
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows of the grid.
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// In reports whether a point lies inside the grid.
+func (g *Grid[T]) In(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// index returns the position of a point in the cells, panicking when it lies outside the grid like a slice would.
+func (g *Grid[T]) index(p Point) int {
+	if !g.In(p) {
+		panic(fmt.Sprintf("point %d,%d out of a %dx%d grid", p.X, p.Y, g.width, g.height))
+	}
+
+	return p.Y*g.width + p.X
+}
+
+// Get returns the value of the cell at a point.
+func (g *Grid[T]) Get(p Point) T {
+	return g.cells[g.index(p)]
+}
+
+// Set changes the value of the cell at a point.
+func (g *Grid[T]) Set(p Point, value T) {
+	g.cells[g.index(p)] = value
+}
+
+// Row returns a copy of the cells of a row.
+func (g *Grid[T]) Row(y int) []T {
+	start := g.index(Point{0, y})
+	return append([]T(nil), g.cells[start:start+g.width]...)
+}
+
+// Column returns a copy of the cells of a column.
+func (g *Grid[T]) Column(x int) []T {
+	result := make([]T, g.height)
+	for y := range result {
+		result[y] = g.Get(Point{x, y})
+	}
+
+	return result
+}
+
+// Points returns every point of the grid, row by row.
+func (g *Grid[T]) Points() []Point {
+	result := make([]Point, 0, len(g.cells))
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			result = append(result, Point{x, y})
+		}
+	}
+
+	return result
+}
+
+// Each calls f with every point of the grid and its value, row by row.
+func (g *Grid[T]) Each(f func(p Point, value T)) {
+	for i, value := range g.cells {
+		f(Point{i % g.width, i / g.width}, value)
+	}
+}
+
+func (g *Grid[T]) neighbours(p Point, offsets []Point) []Point {
+	result := make([]Point, 0, len(offsets))
+	for _, offset := range offsets {
+		if neighbour := p.Add(offset); g.In(neighbour) {
+			result = append(result, neighbour)
+		}
+	}
+
+	return result
+}
+
+// Neighbours4 returns the points of the grid sharing a side with a given one.
+func (g *Grid[T]) Neighbours4(p Point) []Point {
+	return g.neighbours(p, offsets4)
+}
+
+// Neighbours8 returns the points of the grid around a given one, diagonals included.
+func (g *Grid[T]) Neighbours8(p Point) []Point {
+	return g.neighbours(p, offsets8)
+}
+
+// Clone returns a copy of the grid.
+func (g *Grid[T]) Clone() *Grid[T] {
+	return &Grid[T]{width: g.width, height: g.height, cells: append([]T(nil), g.cells...)}
+}
+
+// Grow extends the grid to be at least of the given size, filling the new cells with the zero value.  The grid never
+// shrinks.
+func (g *Grid[T]) Grow(width, height int) {
+	if width <= g.width && height <= g.height {
+		return
+	}
+	if width < g.width {
+		width = g.width
+	}
+	if height < g.height {
+		height = g.height
+	}
+
+	grown := New[T](width, height)
+	for y := 0; y < g.height; y++ {
+		copy(grown.cells[y*width:], g.cells[y*g.width:(y+1)*g.width])
+	}
+	*g = *grown
+}
+
+// transform returns a new grid of the given size, where each cell takes the value of the point of this grid returned by
+// source.
+func (g *Grid[T]) transform(width, height int, source func(p Point) Point) *Grid[T] {
+	result := New[T](width, height)
+	for i := range result.cells {
+		result.cells[i] = g.Get(source(Point{i % width, i / width}))
+	}
+
+	return result
+}
+
+// Transpose returns a new grid with the rows of this one as columns.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	return g.transform(g.height, g.width, func(p Point) Point { return Point{p.Y, p.X} })
+}
+
+// RotateRight returns a new grid with this one rotated 90 degrees clockwise.
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	return g.transform(g.height, g.width, func(p Point) Point { return Point{p.Y, g.height - 1 - p.X} })
+}
+
+// RotateLeft returns a new grid with this one rotated 90 degrees counterclockwise.
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	return g.transform(g.height, g.width, func(p Point) Point { return Point{g.width - 1 - p.Y, p.X} })
+}
+
+// FlipHorizontal returns a new grid mirroring this one left to right.
+func (g *Grid[T]) FlipHorizontal() *Grid[T] {
+	return g.transform(g.width, g.height, func(p Point) Point { return Point{g.width - 1 - p.X, p.Y} })
+}
+
+// FlipVertical returns a new grid mirroring this one top to bottom.
+func (g *Grid[T]) FlipVertical() *Grid[T] {
+	return g.transform(g.width, g.height, func(p Point) Point { return Point{p.X, g.height - 1 - p.Y} })
+}
+
+// Crop returns a new grid with the section of this one of the given size whose top left corner is at origin.  The
+// section must lie inside the grid.
+func (g *Grid[T]) Crop(origin Point, width, height int) *Grid[T] {
+	if width < 0 || height < 0 || origin.X < 0 || origin.Y < 0 || origin.X+width > g.width || origin.Y+height > g.height {
+		panic(fmt.Sprintf("crop of %dx%d at %d,%d out of a %dx%d grid", width, height, origin.X, origin.Y, g.width, g.height))
+	}
+
+	return g.transform(width, height, func(p Point) Point { return p.Add(origin) })
+}
+
+// Map returns a new grid with the result of calling f on every cell of a grid.
+func Map[T, U any](g *Grid[T], f func(p Point, value T) U) *Grid[U] {
+	result := New[U](g.width, g.height)
+	g.Each(func(p Point, value T) {
+		result.cells[p.Y*g.width+p.X] = f(p, value)
+	})
+
+	return result
+}
+
+// Fold combines every cell of a grid, row by row, into an accumulated value starting from initial.
+func Fold[T, A any](g *Grid[T], initial A, f func(accumulated A, p Point, value T) A) A {
+	result := initial
+	g.Each(func(p Point, value T) {
+		result = f(result, p, value)
+	})
+
+	return result
+}
+
+// Print writes the grid row by row, with each cell formatted by cell.
+func (g *Grid[T]) Print(w io.Writer, cell func(value T) string) error {
+	var row strings.Builder
+	for y := 0; y < g.height; y++ {
+		row.Reset()
+		for x := 0; x < g.width; x++ {
+			row.WriteString(cell(g.Get(Point{x, y})))
+		}
+		row.WriteByte('\n')
+
+		if _, err := io.WriteString(w, row.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String formats the grid with the default format of each value, separated by spaces.
+func (g *Grid[T]) String() string {
+	var result strings.Builder
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			if x > 0 {
+				result.WriteByte(' ')
+			}
+			fmt.Fprint(&result, g.Get(Point{x, y}))
+		}
+		result.WriteByte('\n')
+	}
+
+	return result.String()
+}
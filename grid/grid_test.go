@@ -0,0 +1,168 @@
+package grid
+
+import (
+	"advent_2021/extra"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sample is the grid
+//
+//	1 2 3
+//	4 5 6
+func sample(t *testing.T) *Grid[int] {
+	t.Helper()
+
+	g, err := FromRows([][]int{{1, 2, 3}, {4, 5, 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func TestFromRows(t *testing.T) {
+	g := sample(t)
+	if g.Width() != 3 || g.Height() != 2 {
+		t.Errorf("size = %dx%d, want 3x2", g.Width(), g.Height())
+	}
+	if got := g.Get(Point{2, 1}); got != 6 {
+		t.Errorf("Get(2,1) = %d, want 6", got)
+	}
+
+	if _, err := FromRows([][]int{{1, 2}, {3}}); err == nil {
+		t.Error("FromRows() accepted rows of different lengths")
+	}
+}
+
+func TestReadDigits(t *testing.T) {
+	g, err := ReadDigits(extra.NewScanner(strings.NewReader("123\n456\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, sample(t)) {
+		t.Errorf("ReadDigits() = %v, want %v", g, sample(t))
+	}
+}
+
+func TestReadRunes(t *testing.T) {
+	g, err := ReadRunes(extra.NewScanner(strings.NewReader("#.\n.#\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(g.Row(0)) + string(g.Row(1)); got != "#..#" {
+		t.Errorf("ReadRunes() rows = %q, want %q", got, "#..#")
+	}
+
+	if _, err := ReadRunes(extra.NewScanner(strings.NewReader("#.\n.\n"))); err == nil {
+		t.Error("ReadRunes() accepted rows of different lengths")
+	}
+}
+
+func TestRowAndColumn(t *testing.T) {
+	g := sample(t)
+	if got := g.Row(1); !reflect.DeepEqual(got, []int{4, 5, 6}) {
+		t.Errorf("Row(1) = %v, want [4 5 6]", got)
+	}
+	if got := g.Column(1); !reflect.DeepEqual(got, []int{2, 5}) {
+		t.Errorf("Column(1) = %v, want [2 5]", got)
+	}
+
+	// Rows are copies, so changing them leaves the grid intact.
+	g.Row(0)[0] = 9
+	if got := g.Get(Point{0, 0}); got != 1 {
+		t.Errorf("Get(0,0) = %d after changing a row, want 1", got)
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	g := sample(t)
+
+	tests := []struct {
+		name string
+		got  []Point
+		want []Point
+	}{
+		{"4 of a corner", g.Neighbours4(Point{0, 0}), []Point{{1, 0}, {0, 1}}},
+		{"4 of an edge", g.Neighbours4(Point{1, 1}), []Point{{1, 0}, {0, 1}, {2, 1}}},
+		{"8 of a corner", g.Neighbours8(Point{2, 0}), []Point{{1, 0}, {1, 1}, {2, 1}}},
+		{"8 of an edge", g.Neighbours8(Point{1, 0}), []Point{{0, 0}, {2, 0}, {0, 1}, {1, 1}, {2, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformations(t *testing.T) {
+	g := sample(t)
+
+	tests := []struct {
+		name string
+		got  *Grid[int]
+		want string
+	}{
+		{"transpose", g.Transpose(), "1 4\n2 5\n3 6\n"},
+		{"rotate right", g.RotateRight(), "4 1\n5 2\n6 3\n"},
+		{"rotate left", g.RotateLeft(), "3 6\n2 5\n1 4\n"},
+		{"flip horizontal", g.FlipHorizontal(), "3 2 1\n6 5 4\n"},
+		{"flip vertical", g.FlipVertical(), "4 5 6\n1 2 3\n"},
+		{"crop", g.Crop(Point{1, 0}, 2, 2), "2 3\n5 6\n"},
+		{"map", Map(g, func(p Point, value int) int { return value * 10 }), "10 20 30\n40 50 60\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.String(); got != tt.want {
+				t.Errorf("got\n%swant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if got := g.String(); got != "1 2 3\n4 5 6\n" {
+		t.Errorf("the transformations changed the original grid:\n%s", got)
+	}
+}
+
+func TestGrow(t *testing.T) {
+	g := sample(t)
+	g.Grow(4, 3)
+
+	if want := "1 2 3 0\n4 5 6 0\n0 0 0 0\n"; g.String() != want {
+		t.Errorf("Grow(4, 3) =\n%swant\n%s", g, want)
+	}
+
+	g.Grow(1, 1)
+	if g.Width() != 4 || g.Height() != 3 {
+		t.Errorf("Grow(1, 1) shrank the grid to %dx%d", g.Width(), g.Height())
+	}
+}
+
+func TestFold(t *testing.T) {
+	sum := Fold(sample(t), 0, func(accumulated int, p Point, value int) int { return accumulated + value })
+	if sum != 21 {
+		t.Errorf("Fold() = %d, want 21", sum)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var output strings.Builder
+	err := sample(t).Print(&output, func(value int) string {
+		if value%2 == 0 {
+			return "#"
+		}
+		return "."
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := ".#.\n#.#\n"; output.String() != want {
+		t.Errorf("Print() =\n%swant\n%s", output.String(), want)
+	}
+}
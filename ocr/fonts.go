@@ -0,0 +1,42 @@
+package ocr
+
+// smallLetters are the letters 4 cells wide and 6 tall, drawn one column apart, used by most puzzles.
+var smallLetters = map[rune]string{
+	'A': ".##.\n#..#\n#..#\n####\n#..#\n#..#",
+	'B': "###.\n#..#\n###.\n#..#\n#..#\n###.",
+	'C': ".##.\n#..#\n#...\n#...\n#..#\n.##.",
+	'E': "####\n#...\n###.\n#...\n#...\n####",
+	'F': "####\n#...\n###.\n#...\n#...\n#...",
+	'G': ".##.\n#..#\n#...\n#.##\n#..#\n.###",
+	'H': "#..#\n#..#\n####\n#..#\n#..#\n#..#",
+	'I': ".###\n..#.\n..#.\n..#.\n..#.\n.###",
+	'J': "..##\n...#\n...#\n...#\n#..#\n.##.",
+	'K': "#..#\n#.#.\n##..\n#.#.\n#.#.\n#..#",
+	'L': "#...\n#...\n#...\n#...\n#...\n####",
+	'O': ".##.\n#..#\n#..#\n#..#\n#..#\n.##.",
+	'P': "###.\n#..#\n#..#\n###.\n#...\n#...",
+	'R': "###.\n#..#\n#..#\n###.\n#.#.\n#..#",
+	'S': ".###\n#...\n#...\n.##.\n...#\n###.",
+	'U': "#..#\n#..#\n#..#\n#..#\n#..#\n.##.",
+	'Y': "#...\n#...\n.#.#\n..#.\n..#.\n..#.",
+	'Z': "####\n...#\n..#.\n.#..\n#...\n####",
+}
+
+// largeLetters are the letters 6 cells wide and 10 tall, drawn two columns apart.
+var largeLetters = map[rune]string{
+	'A': "..##..\n.#..#.\n#....#\n#....#\n#....#\n######\n#....#\n#....#\n#....#\n#....#",
+	'B': "#####.\n#....#\n#....#\n#....#\n#####.\n#....#\n#....#\n#....#\n#....#\n#####.",
+	'C': ".####.\n#....#\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#....#\n.####.",
+	'E': "######\n#.....\n#.....\n#.....\n#####.\n#.....\n#.....\n#.....\n#.....\n######",
+	'F': "######\n#.....\n#.....\n#.....\n#####.\n#.....\n#.....\n#.....\n#.....\n#.....",
+	'G': ".####.\n#....#\n#.....\n#.....\n#.....\n#..###\n#....#\n#....#\n#...##\n.###.#",
+	'H': "#....#\n#....#\n#....#\n#....#\n######\n#....#\n#....#\n#....#\n#....#\n#....#",
+	'J': "...###\n....#.\n....#.\n....#.\n....#.\n....#.\n....#.\n#...#.\n#...#.\n.###..",
+	'K': "#....#\n#...#.\n#..#..\n#.#...\n##....\n##....\n#.#...\n#..#..\n#...#.\n#....#",
+	'L': "#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n######",
+	'N': "#....#\n##...#\n##...#\n#.#..#\n#.#..#\n#..#.#\n#..#.#\n#...##\n#...##\n#....#",
+	'P': "#####.\n#....#\n#....#\n#....#\n#####.\n#.....\n#.....\n#.....\n#.....\n#.....",
+	'R': "#####.\n#....#\n#....#\n#....#\n#####.\n#..#..\n#...#.\n#...#.\n#....#\n#....#",
+	'X': "#....#\n#....#\n.#..#.\n.#..#.\n..##..\n..##..\n.#..#.\n.#..#.\n#....#\n#....#",
+	'Z': "######\n.....#\n.....#\n....#.\n...#..\n..#...\n.#....\n#.....\n#.....\n######",
+}
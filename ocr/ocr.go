@@ -0,0 +1,205 @@
+// Package ocr reads the text some puzzles draw with dots on a grid, in the block letters they always use.
+package ocr
+
+import (
+	"advent_2021/grid"
+	"fmt"
+	"strings"
+)
+
+// Font is a set of block letters of the same size, drawn side by side with some blank columns between them.
+type Font struct {
+	Width   int
+	Height  int
+	Spacing int
+	glyphs  map[string]rune
+	letters map[rune]string
+}
+
+var (
+	// Small is the font of letters 4 cells wide and 6 tall.
+	Small = mustFont(4, 6, 1, smallLetters)
+	// Large is the font of letters 6 cells wide and 10 tall.
+	Large = mustFont(6, 10, 2, largeLetters)
+	// Fonts are all the known fonts, which Read chooses from.
+	Fonts = []*Font{Small, Large}
+)
+
+// NewFont returns a font from the drawings of its letters, where rows are separated by newlines and dots are drawn as
+// `#` and the rest as `.`.  Every drawing must have the size of the font, and no two letters can look the same.
+func NewFont(width, height, spacing int, letters map[rune]string) (*Font, error) {
+	font := &Font{
+		Width:   width,
+		Height:  height,
+		Spacing: spacing,
+		glyphs:  make(map[string]rune),
+		letters: make(map[rune]string),
+	}
+
+	for letter, drawing := range letters {
+		rows := strings.Split(drawing, "\n")
+		if len(rows) != height {
+			return nil, fmt.Errorf("letter %q is %d rows tall, the font is %d", letter, len(rows), height)
+		}
+		for _, row := range rows {
+			if len(row) != width || strings.Trim(row, "#.") != "" {
+				return nil, fmt.Errorf("letter %q has a row %q which is not %d cells of `#` or `.`", letter, row, width)
+			}
+		}
+
+		glyph := strings.Join(rows, "")
+		if other, ok := font.glyphs[glyph]; ok {
+			return nil, fmt.Errorf("letters %q and %q look the same", other, letter)
+		}
+		font.glyphs[glyph] = letter
+		font.letters[letter] = glyph
+	}
+
+	return font, nil
+}
+
+func mustFont(width, height, spacing int, letters map[rune]string) *Font {
+	font, err := NewFont(width, height, spacing, letters)
+	if err != nil {
+		panic(err)
+	}
+
+	return font
+}
+
+// stride is the number of columns from the start of a letter to the start of the next one.
+func (f *Font) stride() int {
+	return f.Width + f.Spacing
+}
+
+// GlyphError reports the glyphs found on a grid which are not letters of the font.
+type GlyphError struct {
+	// Positions are the positions of the glyphs in the text, starting at 0.
+	Positions []int
+	// Glyphs are the drawings of the glyphs, like those given to NewFont.
+	Glyphs []string
+}
+
+func (e *GlyphError) Error() string {
+	var result strings.Builder
+	for i, position := range e.Positions {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		fmt.Fprintf(&result, "unknown glyph at position %d:\n%s", position, e.Glyphs[i])
+	}
+
+	return result.String()
+}
+
+// bounds returns the number of columns and rows of a grid up to its last dots, which are 0 for a blank grid.
+func bounds(g *grid.Grid[bool]) (width, height int) {
+	g.Each(func(p grid.Point, dot bool) {
+		if dot && p.X >= width {
+			width = p.X + 1
+		}
+		if dot && p.Y >= height {
+			height = p.Y + 1
+		}
+	})
+
+	return width, height
+}
+
+// Read reads the text drawn on a grid, whose first letter starts at its top left corner.  Blank columns and rows
+// after the text are ignored.  Glyphs which are not letters of the font are read as `?`, and reported with a
+// GlyphError.
+func (f *Font) Read(g *grid.Grid[bool]) (string, error) {
+	width, height := bounds(g)
+	if height > f.Height {
+		return "", fmt.Errorf("the text is %d rows tall, letters are %d", height, f.Height)
+	}
+
+	// Dots between letters would be silently lost.
+	for x := f.Width; x < width; x += f.stride() {
+		for y := 0; y < height; y++ {
+			for column := x; column < x+f.Spacing && column < width; column++ {
+				if g.Get(grid.Point{X: column, Y: y}) {
+					return "", fmt.Errorf("there is a dot between two letters at %d,%d", column, y)
+				}
+			}
+		}
+	}
+
+	var text strings.Builder
+	var unknown GlyphError
+	for position := 0; position*f.stride() < width; position++ {
+		var glyph strings.Builder
+		for y := 0; y < f.Height; y++ {
+			for x := position * f.stride(); x < position*f.stride()+f.Width; x++ {
+				if p := (grid.Point{X: x, Y: y}); g.In(p) && g.Get(p) {
+					glyph.WriteByte('#')
+				} else {
+					glyph.WriteByte('.')
+				}
+			}
+		}
+
+		letter, ok := f.glyphs[glyph.String()]
+		if !ok {
+			letter = '?'
+			unknown.Positions = append(unknown.Positions, position)
+			unknown.Glyphs = append(unknown.Glyphs, f.drawing(glyph.String()))
+		}
+		text.WriteRune(letter)
+	}
+
+	if len(unknown.Positions) > 0 {
+		return text.String(), &unknown
+	}
+
+	return text.String(), nil
+}
+
+// drawing splits a glyph in its rows.
+func (f *Font) drawing(glyph string) string {
+	rows := make([]string, f.Height)
+	for y := range rows {
+		rows[y] = glyph[y*f.Width : (y+1)*f.Width]
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// Draw draws a text with the letters of the font on a new grid, failing if some of them are not in the font.
+func (f *Font) Draw(text string) (*grid.Grid[bool], error) {
+	letters := []rune(text)
+	width := 0
+	if len(letters) > 0 {
+		width = len(letters)*f.stride() - f.Spacing
+	}
+	result := grid.New[bool](width, f.Height)
+
+	for position, letter := range letters {
+		glyph, ok := f.letters[letter]
+		if !ok {
+			return nil, fmt.Errorf("there is no letter %q in the font", letter)
+		}
+
+		for i, cell := range glyph {
+			if cell == '#' {
+				result.Set(grid.Point{X: position*f.stride() + i%f.Width, Y: i / f.Width}, true)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Read reads the text drawn on a grid with the font whose letters are as tall as the text, as described by
+// Font.Read.
+func Read(g *grid.Grid[bool]) (string, error) {
+	_, height := bounds(g)
+	for _, font := range Fonts {
+		if font.Height == height {
+			return font.Read(g)
+		}
+	}
+
+	return "", fmt.Errorf("there is no font with letters %d rows tall", height)
+}
@@ -0,0 +1,144 @@
+package ocr
+
+import (
+	"advent_2021/grid"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// parse builds a grid from a drawing, with `#` for dots.
+func parse(t *testing.T, drawing string) *grid.Grid[bool] {
+	t.Helper()
+
+	var rows [][]bool
+	for _, line := range strings.Split(strings.TrimSpace(drawing), "\n") {
+		row := make([]bool, len(line))
+		for x, cell := range line {
+			row[x] = cell == '#'
+		}
+		rows = append(rows, row)
+	}
+
+	g, err := grid.FromRows(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+// alphabet returns every letter of a font, sorted.
+func alphabet(f *Font) string {
+	letters := make([]rune, 0, len(f.letters))
+	for letter := range f.letters {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+	return string(letters)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, font := range Fonts {
+		text := alphabet(font)
+		g, err := font.Draw(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := Read(g); err != nil || got != text {
+			t.Errorf("Read() = %q, %v, want %q", got, err, text)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	// Padded with blank cells on the right and at the bottom, like a folded paper.
+	drawing := `
+#.....##..#..#.####..##..#..#.####...##...
+#....#..#.#..#.#....#..#.#..#.#.......#...
+#....#....####.###..#....#..#.###.....#...
+#....#.##.#..#.#....#.##.#..#.#.......#...
+#....#..#.#..#.#....#..#.#..#.#....#..#...
+####..###.#..#.####..###..##..####..##....
+..........................................
+`
+	if got, err := Read(parse(t, drawing)); err != nil || got != "LGHEGUEJ" {
+		t.Errorf("Read() = %q, %v, want LGHEGUEJ", got, err)
+	}
+
+	if got, err := Small.Read(grid.New[bool](3, 3)); err != nil || got != "" {
+		t.Errorf("Read() of a blank grid = %q, %v, want no text", got, err)
+	}
+}
+
+func TestUnknownGlyphs(t *testing.T) {
+	drawing := `
+####.#....####.
+#..#.#....#..#.
+#..#.#....#..#.
+#..#.#....####.
+#..#.#.......#.
+####.####.####.
+`
+	got, err := Small.Read(parse(t, drawing))
+
+	var glyphErr *GlyphError
+	if !errors.As(err, &glyphErr) {
+		t.Fatalf("Read() error = %v, want a GlyphError", err)
+	}
+	if got != "?L?" || !reflect.DeepEqual(glyphErr.Positions, []int{0, 2}) {
+		t.Errorf("Read() = %q with unknown glyphs at %v, want ?L? with unknown glyphs at [0 2]", got, glyphErr.Positions)
+	}
+	if !strings.Contains(err.Error(), "unknown glyph at position 2:\n####\n#..#\n#..#\n####\n...#\n####") {
+		t.Errorf("Read() error does not show the glyph:\n%v", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		drawing string
+	}{
+		{"dot between letters", "#...#\n#....\n#....\n#....\n#....\n####."},
+		{"too tall", "#\n#\n#\n#\n#\n#\n#"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Small.Read(parse(t, tt.drawing)); err == nil {
+				t.Errorf("Read() = %q, want an error", got)
+			}
+		})
+	}
+
+	if _, err := Read(parse(t, "#\n#\n#")); err == nil {
+		t.Error("Read() found a font for a text 3 rows tall")
+	}
+	if _, err := Small.Draw("AQ"); err == nil {
+		t.Error("Draw() drew a letter which is not in the font")
+	}
+}
+
+func TestNewFontErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		letters map[rune]string
+	}{
+		{"wrong height", map[rune]string{'I': "#\n#"}},
+		{"wrong width", map[rune]string{'I': "#\n##\n#"}},
+		{"wrong cells", map[rune]string{'I': "#\nx\n#"}},
+		{"same glyph", map[rune]string{'I': "#\n#\n#", 'l': "#\n#\n#"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFont(1, 3, 1, tt.letters); err == nil {
+				t.Error("NewFont() accepted an invalid font")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package picture
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Palette gives the colour of a cell from its value.
+type Palette func(value int) color.RGBA
+
+var (
+	// White and Black are the colours of the Mono palette.
+	White = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	Black = color.RGBA{A: 0xff}
+
+	// Mono draws 0 in white and anything else in black, which suits grids of booleans.
+	Mono = Levels(White, Black)
+)
+
+// Levels returns a palette giving each value from 0 on the next colour, where values past the last colour get the last
+// one and negative values get the first one.
+func Levels(colours ...color.RGBA) Palette {
+	return func(value int) color.RGBA {
+		switch {
+		case value < 0:
+			return colours[0]
+		case value >= len(colours):
+			return colours[len(colours)-1]
+		default:
+			return colours[value]
+		}
+	}
+}
+
+// ramp returns a palette going through some colours at even steps as values go from min to max.  Values out of the
+// range get the colour of the closest end.
+func ramp(min, max int, stops ...color.RGBA) Palette {
+	return func(value int) color.RGBA {
+		if max <= min || value <= min {
+			return stops[0]
+		}
+		if value >= max {
+			return stops[len(stops)-1]
+		}
+
+		// Where the value falls between the stops, in units of 1/(max-min) of the distance between two of them.
+		position := (value - min) * (len(stops) - 1)
+		stop, offset := position/(max-min), position%(max-min)
+		from, to := stops[stop], stops[stop+1]
+
+		blend := func(a, b uint8) uint8 {
+			return uint8((int(a)*(max-min-offset) + int(b)*offset) / (max - min))
+		}
+		return color.RGBA{R: blend(from.R, to.R), G: blend(from.G, to.G), B: blend(from.B, to.B), A: 0xff}
+	}
+}
+
+// Gray returns a palette going from black at min to white at max.
+func Gray(min, max int) Palette {
+	return ramp(min, max, Black, White)
+}
+
+// Heat returns a heat map palette going from black at min through red and yellow to white at max.
+func Heat(min, max int) Palette {
+	return ramp(min, max, Black, color.RGBA{R: 0xff, A: 0xff}, color.RGBA{R: 0xff, G: 0xff, A: 0xff}, White)
+}
+
+// PaletteNames are the names of the palettes known by ParsePalette.
+var PaletteNames = []string{"heat", "gray", "mono"}
+
+// ParsePalette returns the palette with a name, for values going from min to max.
+func ParsePalette(name string, min, max int) (Palette, error) {
+	switch name {
+	case "heat":
+		return Heat(min, max), nil
+	case "gray":
+		return Gray(min, max), nil
+	case "mono":
+		return Mono, nil
+	default:
+		return nil, fmt.Errorf("unknown palette %q, it must be one of %s", name, strings.Join(PaletteNames, ", "))
+	}
+}
@@ -0,0 +1,111 @@
+// Package picture exports grids of numbers as PNG or SVG images, where each cell is a square coloured by a palette.
+package picture
+
+import (
+	"advent_2021/grid"
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Drawer is implemented by the puzzles which can draw what a part works on, as a grid of numbers together with the
+// palette that suits it best.
+type Drawer interface {
+	Draw(part int) (*grid.Grid[int], Palette, error)
+}
+
+// Bools turns a grid of booleans into one of numbers, where true is 1 and false is 0.
+func Bools(g *grid.Grid[bool]) *grid.Grid[int] {
+	return grid.Map(g, func(_ grid.Point, value bool) int {
+		if value {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Range returns the smallest and largest values of a grid, which are 0 for an empty grid.
+func Range(g *grid.Grid[int]) (min, max int) {
+	first := true
+	g.Each(func(_ grid.Point, value int) {
+		if first || value < min {
+			min = value
+		}
+		if first || value > max {
+			max = value
+		}
+		first = false
+	})
+
+	return min, max
+}
+
+// Image returns a grid as an image, where each cell is a square of scale pixels per side.
+func Image(g *grid.Grid[int], palette Palette, scale int) *image.RGBA {
+	result := image.NewRGBA(image.Rect(0, 0, g.Width()*scale, g.Height()*scale))
+	g.Each(func(p grid.Point, value int) {
+		colour := palette(value)
+		for y := p.Y * scale; y < (p.Y+1)*scale; y++ {
+			for x := p.X * scale; x < (p.X+1)*scale; x++ {
+				result.SetRGBA(x, y, colour)
+			}
+		}
+	})
+
+	return result
+}
+
+// WritePNG writes a grid as a PNG image, where each cell is a square of scale pixels per side.
+func WritePNG(w io.Writer, g *grid.Grid[int], palette Palette, scale int) error {
+	if scale < 1 {
+		return fmt.Errorf("invalid scale %d, it must be at least 1", scale)
+	}
+
+	return png.Encode(w, Image(g, palette, scale))
+}
+
+// WriteSVG writes a grid as an SVG image, where each cell is a square of scale units per side.  The image is filled
+// with the colour of the first cell, and consecutive cells of a row with the same colour are drawn as a single
+// rectangle, which keeps large grids small.
+func WriteSVG(w io.Writer, g *grid.Grid[int], palette Palette, scale int) error {
+	if scale < 1 {
+		return fmt.Errorf("invalid scale %d, it must be at least 1", scale)
+	}
+
+	output := bufio.NewWriter(w)
+	fmt.Fprintf(output, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d"`+
+		` shape-rendering="crispEdges">`+"\n", g.Width()*scale, g.Height()*scale)
+
+	var background color.RGBA
+	if g.Width() > 0 && g.Height() > 0 {
+		background = palette(g.Get(grid.Point{}))
+		fmt.Fprintf(output, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	}
+
+	for y := 0; y < g.Height(); y++ {
+		row := g.Row(y)
+		for start := 0; start < len(row); {
+			colour, end := palette(row[start]), start+1
+			for end < len(row) && palette(row[end]) == colour {
+				end++
+			}
+
+			if colour != background {
+				fmt.Fprintf(output, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+					start*scale, y*scale, (end-start)*scale, scale, hex(colour))
+			}
+			start = end
+		}
+	}
+
+	fmt.Fprintln(output, "</svg>")
+	return output.Flush()
+}
+
+// hex formats a colour like `#ff8000`.
+func hex(colour color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", colour.R, colour.G, colour.B)
+}
@@ -0,0 +1,113 @@
+package picture
+
+import (
+	"advent_2021/grid"
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// sample is a small grid with values from 0 to 3.
+func sample(t *testing.T) *grid.Grid[int] {
+	t.Helper()
+
+	g, err := grid.FromRows([][]int{{0, 1, 2, 3}, {0, 0, 3, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func TestPalettes(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	tests := []struct {
+		name    string
+		palette Palette
+		value   int
+		want    color.RGBA
+	}{
+		{"mono off", Mono, 0, White},
+		{"mono on", Mono, 1, Black},
+		{"mono many", Mono, 7, Black},
+		{"gray min", Gray(0, 10), 0, Black},
+		{"gray middle", Gray(0, 10), 5, color.RGBA{R: 0x7f, G: 0x7f, B: 0x7f, A: 0xff}},
+		{"gray max", Gray(0, 10), 10, White},
+		{"gray above", Gray(0, 10), 20, White},
+		{"heat red", Heat(0, 3), 1, red},
+		{"heat max", Heat(0, 3), 3, White},
+		{"heat below", Heat(2, 5), -1, Black},
+		{"single value", Heat(4, 4), 4, Black},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.palette(tt.value); got != tt.want {
+				t.Errorf("palette(%d) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParsePalette("rainbow", 0, 1); err == nil {
+		t.Error("ParsePalette() accepted an unknown palette")
+	}
+}
+
+func TestRange(t *testing.T) {
+	if min, max := Range(sample(t)); min != 0 || max != 3 {
+		t.Errorf("Range() = %d, %d, want 0, 3", min, max)
+	}
+
+	dots := grid.New[bool](2, 1)
+	dots.Set(grid.Point{X: 1}, true)
+	if min, max := Range(Bools(dots)); min != 0 || max != 1 {
+		t.Errorf("Range() of booleans = %d, %d, want 0, 1", min, max)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var output bytes.Buffer
+	if err := WritePNG(&output, sample(t), Heat(0, 3), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := png.Decode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 8 || size.Y != 4 {
+		t.Errorf("image is %v, want 8x4", size)
+	}
+
+	// The cell 3,0 covers the pixels from 6,0 to 7,1.
+	for _, pixel := range []struct{ x, y int }{{6, 0}, {7, 1}} {
+		if got := color.RGBAModel.Convert(decoded.At(pixel.x, pixel.y)); got != White {
+			t.Errorf("pixel %d,%d = %v, want white", pixel.x, pixel.y, got)
+		}
+	}
+	if got := color.RGBAModel.Convert(decoded.At(5, 3)); got != White {
+		t.Errorf("pixel 5,3 = %v, want white", got)
+	}
+
+	if err := WritePNG(&output, sample(t), Mono, 0); err == nil {
+		t.Error("WritePNG() accepted a scale of 0")
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var output strings.Builder
+	if err := WriteSVG(&output, sample(t), Levels(White, Black, Black, White), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20" shape-rendering="crispEdges">
+<rect width="100%" height="100%" fill="#ffffff"/>
+<rect x="10" y="0" width="20" height="10" fill="#000000"/>
+</svg>
+`
+	if output.String() != want {
+		t.Errorf("WriteSVG() =\n%swant\n%s", output.String(), want)
+	}
+}
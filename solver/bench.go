@@ -0,0 +1,41 @@
+package solver
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// ParseBenchmark returns a benchmark measuring how long it takes a fresh solver to parse the input.
+func ParseBenchmark(factory Factory, input []byte) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if err := factory().Parse(bytes.NewReader(input)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// PartBenchmark returns a benchmark measuring how long it takes to solve a part.  The input is parsed only once, before
+// starting the timer, as solving a part never alters it.  Parts which are not solved yet are skipped.
+func PartBenchmark(factory Factory, input []byte, part int) func(b *testing.B) {
+	return func(b *testing.B) {
+		s := factory()
+		if err := s.Parse(bytes.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := Solve(s, part); errors.Is(err, ErrNotSolved) {
+				b.Skip(err)
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package solver
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Factory creates a new, empty, Solver for a day.
+type Factory func() Solver
+
+// registry keeps the solver factory of every day, indexed by the day number.
+var registry = make(map[int]Factory)
+
+// Register makes the solver of a given day available to the runner.  It is meant to be called from the init function
+// of each day package, so registering the same day twice is a programming error, and we panic about it.
+func Register(day int, factory Factory) {
+	if _, ok := registry[day]; ok {
+		panic(fmt.Sprintf("day %d registered twice", day))
+	}
+
+	registry[day] = factory
+}
+
+// New returns a fresh Solver for a given day, or false in case the day is unknown.
+func New(day int) (Solver, bool) {
+	factory, ok := registry[day]
+	if !ok {
+		return nil, false
+	}
+
+	return factory(), true
+}
+
+// Days returns the sorted list of all registered days.
+func Days() (result []int) {
+	for day := range registry {
+		result = append(result, day)
+	}
+	sort.Ints(result)
+
+	return result
+}
+
+// InputPath returns the default location of the input of a given day, relative to the root of the repository.
+func InputPath(day int) string {
+	return fmt.Sprintf("inputs/day%02d_exercise01.txt", day)
+}
+
+// ExamplePath returns the location of the example input published with the puzzle of a given day, relative to the root
+// of the repository.
+func ExamplePath(day int) string {
+	return fmt.Sprintf("%02d/testdata/example.txt", day)
+}
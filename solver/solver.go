@@ -0,0 +1,64 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrNotSolved is returned by the parts which are not solved yet, like those of a new day.
+var ErrNotSolved = errors.New("not solved yet")
+
+// Solver is the contract every day implements.  The input is parsed once, and both parts are then solved from the
+// parsed data, so a part must never modify it.
+type Solver interface {
+	// Parse reads and stores the puzzle input.
+	Parse(input io.Reader) error
+	// PartOne solves the first exercise of the day.
+	PartOne() (Answer, error)
+	// PartTwo solves the second exercise of the day.
+	PartTwo() (Answer, error)
+}
+
+// Answer is the result of solving a part.  Most puzzles expect a number, but a few of them expect some text instead.
+type Answer struct {
+	number int
+	text   string
+	isText bool
+}
+
+// Number returns an Answer holding an integer.
+func Number(value int) Answer {
+	return Answer{number: value}
+}
+
+// Text returns an Answer holding a string.
+func Text(value string) Answer {
+	return Answer{text: value, isText: true}
+}
+
+// Int returns the numeric value of the answer, and false if the answer is a text one.
+func (a Answer) Int() (int, bool) {
+	return a.number, !a.isText
+}
+
+func (a Answer) String() string {
+	if a.isText {
+		return a.text
+	}
+
+	return strconv.Itoa(a.number)
+}
+
+// Solve runs the requested part, 1 or 2, of an already parsed Solver.
+func Solve(s Solver, part int) (Answer, error) {
+	switch part {
+	case 1:
+		return s.PartOne()
+	case 2:
+		return s.PartTwo()
+	default:
+		return Answer{}, fmt.Errorf("unknown part %d", part)
+	}
+}
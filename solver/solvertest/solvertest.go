@@ -0,0 +1,65 @@
+// Package solvertest provides a table-driven harness to test the solvers of every day against known inputs.
+package solvertest
+
+import (
+	"advent_2021/solver"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Case is an input together with the answers expected for each part.  An empty answer skips that part, which is
+// useful for the examples published for only one of them.
+type Case struct {
+	Name    string
+	Input   string
+	PartOne string
+	PartTwo string
+}
+
+// Run parses the input of every case with a fresh solver and checks the answer of both parts.  Each part is solved
+// twice, to make sure solving never alters the parsed input.
+func Run(t *testing.T, factory solver.Factory, cases []Case) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			s := factory()
+			if err := s.Parse(strings.NewReader(tc.Input)); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			for i, want := range []string{tc.PartOne, tc.PartTwo} {
+				part := i + 1
+				if want == "" {
+					continue
+				}
+
+				for attempt := 1; attempt <= 2; attempt++ {
+					got, err := solver.Solve(s, part)
+					if err != nil {
+						t.Fatalf("part %d, attempt %d: error = %v", part, attempt, err)
+					}
+					if got.String() != want {
+						t.Errorf("part %d, attempt %d: got %s, want %s", part, attempt, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Benchmark runs the parse and solve benchmarks of a day as sub-benchmarks.  They use the real input of the day, read
+// relative to the package directory, or the given example when the real input is not available.
+func Benchmark(b *testing.B, day int, factory solver.Factory, example string) {
+	input, err := os.ReadFile(filepath.Join("..", solver.InputPath(day)))
+	if err != nil {
+		b.Logf("using the example, as the input is not available: %v", err)
+		input = []byte(example)
+	}
+
+	b.Run("parse", solver.ParseBenchmark(factory, input))
+	b.Run("part1", solver.PartBenchmark(factory, input, 1))
+	b.Run("part2", solver.PartBenchmark(factory, input, 2))
+}
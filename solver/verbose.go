@@ -0,0 +1,15 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+)
+
+// Verbose receives the diagnostic output of the solvers, like intermediate states of a puzzle.  It discards everything
+// unless the runner is asked to show it, so the answers are never mixed with it.
+var Verbose io.Writer = io.Discard
+
+// Debugf writes diagnostic output to Verbose.
+func Debugf(format string, a ...interface{}) {
+	fmt.Fprintf(Verbose, format, a...)
+}